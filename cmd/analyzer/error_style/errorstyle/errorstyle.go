@@ -0,0 +1,338 @@
+// Package errorstyle checks that errors are built the same way in the whole app.
+package errorstyle
+
+import (
+	"bytes"
+	"go/ast"
+	"go/constant"
+	"go/printer"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const appPath = "github.com/MrEhbr/app"
+
+const doc = `check that errors are built the same way in the whole app
+
+The analyzer reports:
+  - errors.New and fmt.Errorf returned from exported functions without app.OpError or app.ErrorWithCode;
+  - app.Error literals with neither Code nor Err;
+  - constant Op that doesn't match the enclosing function;
+  - app.ErrorWithCode with nil error.`
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "error_style",
+	Doc:      doc,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	nodeFilter := []ast.Node{
+		(*ast.ReturnStmt)(nil),
+		(*ast.CallExpr)(nil),
+		(*ast.CompositeLit)(nil),
+	}
+
+	insp.WithStack(nodeFilter, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+
+		file, _ := stack[0].(*ast.File)
+		switch node := n.(type) {
+		case *ast.ReturnStmt:
+			checkReturn(pass, file, node, stack)
+		case *ast.CallExpr:
+			checkCall(pass, file, node, stack)
+		case *ast.CompositeLit:
+			checkLiteral(pass, node, stack)
+		}
+
+		return true
+	})
+
+	return nil, nil
+}
+
+// checkReturn reports errors.New and fmt.Errorf returned from exported functions.
+func checkReturn(pass *analysis.Pass, file *ast.File, ret *ast.ReturnStmt, stack []ast.Node) {
+	decl, ok := enclosingFunc(stack, true)
+	if !ok || !decl.Name.IsExported() {
+		return
+	}
+
+	for _, res := range ret.Results {
+		call, ok := astutil.Unparen(res).(*ast.CallExpr)
+		if !ok || !(isFunc(pass, call, "errors", "New") || isFunc(pass, call, "fmt", "Errorf")) {
+			continue
+		}
+
+		diag := analysis.Diagnostic{
+			Pos:     res.Pos(),
+			End:     res.End(),
+			Message: "error returned from exported function " + funcName(decl) + " should be wrapped with app.OpError or app.ErrorWithCode",
+		}
+		if qual, ok := appQualifier(pass, file); ok {
+			diag.SuggestedFixes = []analysis.SuggestedFix{{
+				Message: "Wrap with app.OpError",
+				TextEdits: []analysis.TextEdit{
+					{Pos: res.Pos(), End: res.Pos(), NewText: []byte(qual + "OpError(" + strconv.Quote(funcName(decl)) + ", ")},
+					{Pos: res.End(), End: res.End(), NewText: []byte(")")},
+				},
+			}}
+		}
+		pass.Report(diag)
+	}
+}
+
+// checkCall reports constant ops of app.OpError and app.ErrorWithCode with nil error.
+func checkCall(pass *analysis.Pass, file *ast.File, call *ast.CallExpr, stack []ast.Node) {
+	switch {
+	case isFunc(pass, call, appPath, "OpError"), isFunc(pass, call, appPath, "OpErrorOrNil"):
+		if len(call.Args) > 0 {
+			checkOp(pass, call.Args[0], stack)
+		}
+	case isFunc(pass, call, appPath, "ErrorWithCode"):
+		if len(call.Args) != 2 || !pass.TypesInfo.Types[call.Args[0]].IsNil() {
+			return
+		}
+
+		diag := analysis.Diagnostic{
+			Pos:     call.Pos(),
+			End:     call.End(),
+			Message: "app.ErrorWithCode called with nil error, use app.NewError",
+		}
+		if qual, ok := appQualifier(pass, file); ok {
+			diag.SuggestedFixes = []analysis.SuggestedFix{{
+				Message: "Replace with app.NewError",
+				TextEdits: []analysis.TextEdit{{
+					Pos:     call.Pos(),
+					End:     call.End(),
+					NewText: []byte(qual + "NewError(" + render(pass.Fset, call.Args[1]) + `, "")`),
+				}},
+			}}
+		}
+		pass.Report(diag)
+	}
+}
+
+// checkLiteral reports app.Error literals without Code and Err, and constant ops.
+func checkLiteral(pass *analysis.Pass, lit *ast.CompositeLit, stack []ast.Node) {
+	if !isAppError(pass.TypesInfo.TypeOf(lit)) {
+		return
+	}
+
+	hasCodeOrErr := false
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			// unkeyed literal sets all fields
+			return
+		}
+
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+
+		switch key.Name {
+		case "Code", "Err":
+			hasCodeOrErr = true
+		case "Op":
+			checkOp(pass, kv.Value, stack)
+		}
+	}
+
+	if hasCodeOrErr || (len(lit.Elts) == 0 && isTarget(stack)) {
+		return
+	}
+
+	code := "EINTERNAL"
+	if sel, ok := lit.Type.(*ast.SelectorExpr); ok {
+		code = render(pass.Fset, sel.X) + "." + code
+	}
+
+	edit := analysis.TextEdit{Pos: lit.Rbrace, End: lit.Rbrace, NewText: []byte("Code: " + code)}
+	if len(lit.Elts) > 0 {
+		last := lit.Elts[len(lit.Elts)-1]
+		edit = analysis.TextEdit{Pos: last.End(), End: last.End(), NewText: []byte(", Code: " + code)}
+	}
+
+	pass.Report(analysis.Diagnostic{
+		Pos:     lit.Pos(),
+		End:     lit.End(),
+		Message: "app.Error literal has neither Code nor Err",
+		SuggestedFixes: []analysis.SuggestedFix{{
+			Message:   "Add Code",
+			TextEdits: []analysis.TextEdit{edit},
+		}},
+	})
+}
+
+// checkOp reports constant op that doesn't match the enclosing function.
+func checkOp(pass *analysis.Pass, expr ast.Expr, stack []ast.Node) {
+	tv, ok := pass.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return
+	}
+
+	decl, ok := enclosingFunc(stack, false)
+	if !ok {
+		return
+	}
+
+	op := constant.StringVal(tv.Value)
+	if op == decl.Name.Name || strings.HasSuffix(op, "."+decl.Name.Name) {
+		return
+	}
+
+	diag := analysis.Diagnostic{
+		Pos:     expr.Pos(),
+		End:     expr.End(),
+		Message: "op " + strconv.Quote(op) + " doesn't match enclosing function " + funcName(decl),
+	}
+	if _, ok := expr.(*ast.BasicLit); ok {
+		diag.SuggestedFixes = []analysis.SuggestedFix{{
+			Message: "Use enclosing function name",
+			TextEdits: []analysis.TextEdit{{
+				Pos:     expr.Pos(),
+				End:     expr.End(),
+				NewText: []byte(strconv.Quote(funcName(decl))),
+			}},
+		}}
+	}
+	pass.Report(diag)
+}
+
+// isTarget reports whether the literal on top of the stack is assigned to a variable
+// or passed to a call, e.g. the target of errors.As, errors.Is or the interface assertion.
+func isTarget(stack []ast.Node) bool {
+	i := len(stack) - 2
+	if i >= 0 {
+		if unary, ok := stack[i].(*ast.UnaryExpr); ok && unary.Op == token.AND {
+			i--
+		}
+	}
+	if i < 0 {
+		return false
+	}
+
+	switch n := stack[i].(type) {
+	case *ast.AssignStmt, *ast.ValueSpec:
+		return true
+	case *ast.CallExpr:
+		// the literal is an argument, not the called function
+		return n.Fun != stack[i+1]
+	}
+
+	return false
+}
+
+// enclosingFunc returns the nearest function declaration of the stack.
+// If direct is true, returns false when a function literal is nearer.
+func enclosingFunc(stack []ast.Node, direct bool) (*ast.FuncDecl, bool) {
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch n := stack[i].(type) {
+		case *ast.FuncDecl:
+			return n, true
+		case *ast.FuncLit:
+			if direct {
+				return nil, false
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// funcName returns the name of the function in form of "Func" or "Type.Method".
+func funcName(decl *ast.FuncDecl) string {
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return decl.Name.Name
+	}
+
+	typ := decl.Recv.List[0].Type
+	for {
+		switch t := typ.(type) {
+		case *ast.StarExpr:
+			typ = t.X
+			continue
+		case *ast.IndexExpr:
+			typ = t.X
+			continue
+		case *ast.IndexListExpr:
+			typ = t.X
+			continue
+		case *ast.Ident:
+			return t.Name + "." + decl.Name.Name
+		}
+
+		return decl.Name.Name
+	}
+}
+
+func isFunc(pass *analysis.Pass, call *ast.CallExpr, pkg, name string) bool {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil {
+		return false
+	}
+
+	return fn.Pkg().Path() == pkg && fn.Name() == name
+}
+
+func isAppError(typ types.Type) bool {
+	named, ok := typ.(*types.Named)
+	if !ok {
+		return false
+	}
+
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == appPath && obj.Name() == "Error"
+}
+
+// appQualifier returns the qualifier of the app package in the file.
+func appQualifier(pass *analysis.Pass, file *ast.File) (string, bool) {
+	if pass.Pkg.Path() == appPath {
+		return "", true
+	}
+
+	if file == nil {
+		return "", false
+	}
+
+	for _, imp := range file.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil || path != appPath {
+			continue
+		}
+
+		if imp.Name == nil {
+			return "app.", true
+		}
+		if imp.Name.Name == "_" || imp.Name.Name == "." {
+			return "", imp.Name.Name == "."
+		}
+		return imp.Name.Name + ".", true
+	}
+
+	return "", false
+}
+
+func render(fset *token.FileSet, expr ast.Expr) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, expr); err != nil {
+		return types.ExprString(expr)
+	}
+
+	return buf.String()
+}
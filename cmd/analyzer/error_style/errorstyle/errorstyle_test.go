@@ -0,0 +1,12 @@
+package errorstyle_test
+
+import (
+	"testing"
+
+	"github.com/MrEhbr/app/cmd/analyzer/error_style/errorstyle"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), errorstyle.Analyzer, "a")
+}
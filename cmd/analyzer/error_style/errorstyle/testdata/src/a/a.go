@@ -0,0 +1,97 @@
+package a
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/MrEhbr/app"
+)
+
+type Service struct{}
+
+func Exported() error {
+	return errors.New("foo") // want `error returned from exported function Exported should be wrapped with app.OpError or app.ErrorWithCode`
+}
+
+func ExportedErrorf(id int) (int, error) {
+	return 0, fmt.Errorf("bad id %d", id) // want `error returned from exported function ExportedErrorf should be wrapped with app.OpError or app.ErrorWithCode`
+}
+
+func (s *Service) Method() error {
+	return errors.New("foo") // want `error returned from exported function Service.Method should be wrapped with app.OpError or app.ErrorWithCode`
+}
+
+func Wrapped() error {
+	return app.OpError("Wrapped", errors.New("foo"))
+}
+
+func WithCode() error {
+	return app.ErrorWithCode(errors.New("foo"), app.ENOTFOUND)
+}
+
+func Closure() func() error {
+	return func() error {
+		return errors.New("foo")
+	}
+}
+
+func unexported() error {
+	return errors.New("foo")
+}
+
+func Literal() error {
+	return &app.Error{Message: "foo"} // want `app.Error literal has neither Code nor Err`
+}
+
+func EmptyLiteral() error {
+	return &app.Error{} // want `app.Error literal has neither Code nor Err`
+}
+
+func LiteralWithCode() error {
+	return &app.Error{Code: app.ENOTFOUND, Message: "foo"}
+}
+
+func LiteralWithErr() error {
+	return &app.Error{Op: "LiteralWithErr", Err: unexported()}
+}
+
+func WrongOp() error {
+	return app.OpError("Other", unexported()) // want `op "Other" doesn't match enclosing function WrongOp`
+}
+
+func (s Service) WrongMethodOp() error {
+	return app.OpErrorOrNil("Service.Other", unexported()) // want `op "Service.Other" doesn't match enclosing function Service.WrongMethodOp`
+}
+
+func (s Service) MethodOp() error {
+	return app.OpError("a.Service.MethodOp", unexported())
+}
+
+func WrongLiteralOp() error {
+	return &app.Error{Op: "Other", Code: app.ENOTFOUND} // want `op "Other" doesn't match enclosing function WrongLiteralOp`
+}
+
+const op = "Other"
+
+func ConstOp() error {
+	return app.OpError(op, unexported()) // want `op "Other" doesn't match enclosing function ConstOp`
+}
+
+func DynamicOp(name string) error {
+	return app.OpError(name, unexported())
+}
+
+func NilErr() error {
+	return app.ErrorWithCode(nil, app.ENOTFOUND) // want `app.ErrorWithCode called with nil error, use app.NewError`
+}
+
+func Target(err error) bool {
+	target := &app.Error{}
+	return errors.As(err, &target)
+}
+
+func IsAppError(err error) bool {
+	return errors.Is(err, &app.Error{})
+}
+
+var _ error = &app.Error{}
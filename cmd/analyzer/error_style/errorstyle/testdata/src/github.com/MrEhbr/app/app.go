@@ -0,0 +1,26 @@
+package app
+
+type Code string
+
+const (
+	EINTERNAL = Code("internal")
+	ENOTFOUND = Code("not_found")
+)
+
+type Error struct {
+	Err     error
+	Fields  map[string]interface{}
+	Code    Code
+	Message string
+	Op      string
+}
+
+func (e *Error) Error() string { return e.Message }
+
+func NewError(code Code, message string) error { return &Error{Code: code, Message: message} }
+
+func ErrorWithCode(err error, code Code) error { return &Error{Err: err, Code: code} }
+
+func OpError(op string, err error) error { return &Error{Op: op, Err: err} }
+
+func OpErrorOrNil(op string, err error) error { return &Error{Op: op, Err: err} }
@@ -0,0 +1,17 @@
+// Command error_style checks that errors are built the same way in the whole app.
+//
+// The command is a separate module, so it follows the latest golang.org/x/tools
+// required by new Go toolchains, while the app keeps supporting older ones.
+// Build it with make go.build and run from the module to check:
+//
+//	.bin/error_style/error_style ./...
+package main
+
+import (
+	"github.com/MrEhbr/app/cmd/analyzer/error_style/errorstyle"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(errorstyle.Analyzer)
+}
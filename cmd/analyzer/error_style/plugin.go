@@ -0,0 +1,21 @@
+package main
+
+import (
+	"github.com/MrEhbr/app/cmd/analyzer/error_style/errorstyle"
+	"golang.org/x/tools/go/analysis"
+)
+
+type analyzerPlugin struct{}
+
+// GetAnalyzers returns analyzers of the golangci-lint plugin.
+func (analyzerPlugin) GetAnalyzers() []*analysis.Analyzer {
+	return []*analysis.Analyzer{errorstyle.Analyzer}
+}
+
+// AnalyzerPlugin is the golangci-lint plugin, build with make go.build_plugin.
+var AnalyzerPlugin analyzerPlugin
+
+// New returns analyzers of the golangci-lint plugin.
+func New(conf interface{}) ([]*analysis.Analyzer, error) {
+	return AnalyzerPlugin.GetAnalyzers(), nil
+}
@@ -0,0 +1,68 @@
+package app
+
+import (
+	"context"
+
+	"github.com/MrEhbr/app/internal/mapx"
+)
+
+type fieldsKey struct{}
+
+// WithFields returns the copy of the ctx with the fields merged into fields of the ctx,
+// the fields override the ones of the ctx.
+// The fields are added to errors created with OpErrorCtx and ErrorWithCodeCtx.
+func WithFields(ctx context.Context, fields map[string]interface{}) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+
+	parent := FieldsFromContext(ctx)
+	merged := make(map[string]interface{}, len(parent)+len(fields))
+	for k, v := range parent {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+
+	return context.WithValue(ctx, fieldsKey{}, merged)
+}
+
+// FieldsFromContext returns fields of the ctx added with WithFields, the returned map must not be modified.
+func FieldsFromContext(ctx context.Context) map[string]interface{} {
+	if ctx == nil {
+		return nil
+	}
+
+	fields, _ := ctx.Value(fieldsKey{}).(map[string]interface{})
+	return fields
+}
+
+// OpErrorCtx is like OpError, but fields of the ctx are added to the returned Error,
+// fields of the err take precedence over the ctx ones.
+func OpErrorCtx(ctx context.Context, op string, err error) error {
+	e := opError(op, err, newStack(err))
+	e.Fields = contextFields(ctx)
+
+	return e
+}
+
+// ErrorWithCodeCtx is like ErrorWithCode, but fields of the ctx are added to the returned Error,
+// fields of the err take precedence over the ctx ones.
+func ErrorWithCodeCtx(ctx context.Context, err error, code Code) error {
+	e := errorWithCode(err, code, CallerFunctionName(), newStack(err))
+	e.Fields = contextFields(ctx)
+
+	return e
+}
+
+// contextFields returns a copy of fields of the ctx for the wrapper,
+// fields of the err override them, because ErrorFields lets inner errors override outer ones.
+func contextFields(ctx context.Context) map[string]interface{} {
+	fields := FieldsFromContext(ctx)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return mapx.Merge(fields, nil)
+}
@@ -0,0 +1,130 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWithFields(t *testing.T) {
+	ctx := WithFields(context.Background(), map[string]interface{}{"request_id": "1", "tenant": "acme"})
+	ctx = WithFields(ctx, map[string]interface{}{"user_id": 2, "tenant": "other"})
+
+	want := map[string]interface{}{"request_id": "1", "tenant": "other", "user_id": 2}
+	if diff := cmp.Diff(want, FieldsFromContext(ctx)); diff != "" {
+		t.Fatalf("fields mismatch (-want +got):\n%s", diff)
+	}
+
+	if got := FieldsFromContext(context.Background()); got != nil {
+		t.Fatalf("want nil, got: %v", got)
+	}
+}
+
+func TestOpErrorCtx(t *testing.T) {
+	ctx := WithFields(context.Background(), map[string]interface{}{"request_id": "1", "tenant": "acme"})
+
+	tests := []struct {
+		name       string
+		err        error
+		wantFields map[string]interface{}
+		wantTrace  []string
+	}{
+		{
+			name:       "std error",
+			err:        OpErrorCtx(ctx, "op", errors.New("foo")),
+			wantFields: map[string]interface{}{"request_id": "1", "tenant": "acme"},
+			wantTrace:  []string{"op"},
+		},
+		{
+			name:       "error without op",
+			err:        OpErrorCtx(ctx, "op", &Error{Code: ENOTFOUND, Fields: map[string]interface{}{"tenant": "error"}}),
+			wantFields: map[string]interface{}{"request_id": "1", "tenant": "error"},
+			wantTrace:  []string{"op"},
+		},
+		{
+			name:       "error with op",
+			err:        OpErrorCtx(ctx, "op", &Error{Op: "inner", Code: ENOTFOUND, Fields: map[string]interface{}{"tenant": "error"}}),
+			wantFields: map[string]interface{}{"request_id": "1", "tenant": "error"},
+			wantTrace:  []string{"op", "inner"},
+		},
+		{
+			name:       "error with code",
+			err:        ErrorWithCodeCtx(ctx, &Error{Op: "inner", Fields: map[string]interface{}{"tenant": "error"}}, ENOTFOUND),
+			wantFields: map[string]interface{}{"request_id": "1", "tenant": "error"},
+			wantTrace:  []string{"inner"},
+		},
+		{
+			name:       "std error with code",
+			err:        ErrorWithCodeCtx(ctx, errors.New("foo"), ENOTFOUND),
+			wantFields: map[string]interface{}{"request_id": "1", "tenant": "acme"},
+			wantTrace:  []string{"github.com/MrEhbr/app.TestOpErrorCtx"},
+		},
+		{
+			name:       "no context fields",
+			err:        OpErrorCtx(context.Background(), "op", errors.New("foo")),
+			wantFields: map[string]interface{}{},
+			wantTrace:  []string{"op"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.wantFields, ErrorFields(tt.err)); diff != "" {
+				t.Fatalf("fields mismatch (-want +got):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tt.wantTrace, ErrorTrace(tt.err)); diff != "" {
+				t.Fatalf("trace mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOpErrorCtx_sentinel(t *testing.T) {
+	sentinel := &Error{Code: ENOTFOUND, Fields: map[string]interface{}{"entity": "user"}}
+
+	ctxA := WithFields(context.Background(), map[string]interface{}{"request_id": "A", "user_id": "alice"})
+	ctxB := WithFields(context.Background(), map[string]interface{}{"request_id": "B"})
+
+	errA := OpErrorCtx(ctxA, "op", sentinel)
+	errB := OpErrorCtx(ctxB, "op", sentinel)
+
+	if diff := cmp.Diff(map[string]interface{}{"entity": "user", "request_id": "A", "user_id": "alice"}, ErrorFields(errA)); diff != "" {
+		t.Fatalf("first error fields mismatch (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(map[string]interface{}{"entity": "user", "request_id": "B"}, ErrorFields(errB)); diff != "" {
+		t.Fatalf("second error fields mismatch (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(map[string]interface{}{"entity": "user"}, sentinel.Fields); diff != "" {
+		t.Fatalf("sentinel fields modified (-want +got):\n%s", diff)
+	}
+}
+
+func TestOpErrorCtx_sentinelOp(t *testing.T) {
+	sentinel := &Error{Code: ENOTFOUND}
+	ctx := WithFields(context.Background(), map[string]interface{}{"request_id": "1"})
+
+	users := OpErrorCtx(ctx, "users.Get", sentinel)
+	orders := OpErrorCtx(ctx, "orders.Get", sentinel)
+	coded := ErrorWithCodeCtx(ctx, sentinel, ECONFLICT)
+
+	if sentinel.Op != "" || sentinel.Code != ENOTFOUND {
+		t.Fatalf("sentinel modified: %#v", sentinel)
+	}
+
+	if diff := cmp.Diff([]string{"users.Get"}, ErrorTrace(users)); diff != "" {
+		t.Fatalf("first trace mismatch (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff([]string{"orders.Get"}, ErrorTrace(orders)); diff != "" {
+		t.Fatalf("second trace mismatch (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff([]string{"github.com/MrEhbr/app.TestOpErrorCtx_sentinelOp"}, ErrorTrace(coded)); diff != "" {
+		t.Fatalf("code trace mismatch (-want +got):\n%s", diff)
+	}
+}
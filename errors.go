@@ -3,45 +3,47 @@ package app
 import (
 	"errors"
 	"strings"
+	"time"
 )
 
-type errorCode string
+// Code is a machine-readable error code, custom codes can be declared with RegisterCode.
+type Code string
 
-func (code errorCode) String() string {
+func (code Code) String() string {
 	return string(code)
 }
 
 // Application error codes.
 const (
 	// ECONFLICT Action cannot be performed.
-	ECONFLICT errorCode = "conflict"
+	ECONFLICT Code = "conflict"
 	// EINTERNAL internal error.
-	EINTERNAL errorCode = "internal"
+	EINTERNAL Code = "internal"
 	// EINVALID validation failed.
-	EINVALID errorCode = "invalid"
+	EINVALID Code = "invalid"
 	// ENOTFOUND entity not found/doesn't exist.
-	ENOTFOUND errorCode = "not_found"
+	ENOTFOUND Code = "not_found"
 	// ENOTMODIFIED entity not modified.
-	ENOTMODIFIED errorCode = "not_modified"
+	ENOTMODIFIED Code = "not_modified"
 	// EALREADYEXISTS entity already exists.
-	EALREADYEXISTS errorCode = "already_exists"
+	EALREADYEXISTS Code = "already_exists"
 	// EPermissionDenied user does not have permission.
-	EPERMISSIONDENIED errorCode = "permission_denied"
+	EPERMISSIONDENIED Code = "permission_denied"
 	// EUNAUTHENTICATED Requestor does not have valid authentication to perform to operation.
-	EUNAUTHENTICATED errorCode = "unauthenticated"
+	EUNAUTHENTICATED Code = "unauthenticated"
 	// ECANNOTDECODE Data could not be decoded.
-	ECANNOTDECODE errorCode = "cannot_decode"
+	ECANNOTDECODE Code = "cannot_decode"
 	// ECANNOTENCODE Data could not be encoded.
-	ECANNOTENCODE errorCode = "cannot_encode"
+	ECANNOTENCODE Code = "cannot_encode"
 	// ECANNOTPARSE Data could not be parsed.
-	ECANNOTPARSE errorCode = "cannot_parse"
+	ECANNOTPARSE Code = "cannot_parse"
 	// EBEHAVIOUR something that must not be.
-	EBEHAVIOUR errorCode = "undefined_behavior"
+	EBEHAVIOUR Code = "undefined_behavior"
 	// EUNSUPPORTED means that we dont support some actions
 	// and this actions should be handled by others.
-	EUNSUPPORTED errorCode = "unsupported"
+	EUNSUPPORTED Code = "unsupported"
 	// ETEST test error code, useful for testing.
-	ETEST errorCode = "test_error_code"
+	ETEST Code = "test_error_code"
 
 	DefaultErrorMessage = "An internal error has occurred"
 )
@@ -51,13 +53,27 @@ type Error struct {
 	// Wrapped error
 	Err error `json:"err"`
 	// Some context of error
-	Fields map[string]interface{}
+	Fields map[string]interface{} `json:"fields"`
 	// Machine-readable error code.
-	Code errorCode `json:"code"`
-	// Human-readable message.
+	Code Code `json:"code"`
+	// Human-readable message, safe to show to users, see PublicMessage.
 	Message string `json:"message"`
+	// Internal detail for developers, never shown to users, see DetailMessage.
+	Detail string `json:"detail,omitempty"`
+	// Key of the localized message of the Code, see LocalizedMessage.
+	MessageKey string `json:"message_key,omitempty"`
+	// Custom grouping key, overrides the computed identity of the error, see Fingerprint.
+	GroupKey string `json:"group_key,omitempty"`
 	// Logical operation.
 	Op string `json:"op"`
+	// Retryable overrides retryability of the Code, if not nil.
+	Retryable *bool `json:"retryable,omitempty"`
+	// Temporary overrides temporariness of the Code, if not nil.
+	Temporary *bool `json:"temporary,omitempty"`
+	// Hint how long to wait before retry.
+	RetryAfter time.Duration `json:"retry_after,omitempty"`
+	// Stack captured on creation, if enabled.
+	Stack Stack `json:"-"`
 }
 
 func (e *Error) Unwrap() error { return e.Err }
@@ -95,12 +111,16 @@ func (e *Error) Error() string {
 }
 
 // ErrorCode returns the code of the error, if available. Otherwise returns EINTERNAL.
-func ErrorCode(err error) errorCode {
+// For Errors the dominant code is returned.
+func ErrorCode(err error) Code {
 	if err == nil {
 		return ""
 	}
-	target := &Error{}
-	if errors.As(err, &target) {
+	target, errs := find(err)
+	if errs != nil {
+		return errs.Code()
+	}
+	if target != nil {
 		if target.Code != "" {
 			return target.Code
 		}
@@ -121,12 +141,18 @@ func ErrorMessage(err error) string {
 
 // ErrorMessage returns the human-readable message of the error, if available.
 // Otherwise returns default message if default is not empty, otherwise return err.Error().
+// For Errors the message of the dominant error is returned.
 func ErrorMessageDefault(err error, def string) string {
 	if err == nil {
 		return ""
 	}
-	target := &Error{}
-	if errors.As(err, &target) {
+	target, errs := find(err)
+	if errs != nil {
+		if dominant := errs.dominant(); dominant != nil {
+			return ErrorMessageDefault(dominant, def)
+		}
+	}
+	if target != nil {
 		if target.Message != "" {
 			return target.Message
 		}
@@ -143,13 +169,70 @@ func ErrorMessageDefault(err error, def string) string {
 	return err.Error()
 }
 
+// PublicMessage returns the message of the err safe to show to users:
+//   - DefaultErrorMessage if the code of the err is EINTERNAL, internal errors never expose their text;
+//   - the first non-empty Error.Message of the chain, for Errors the chain of the dominant error is used;
+//   - DefaultErrorMessage otherwise.
+//
+// Error.Detail and messages of non-app errors are never returned.
+func PublicMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	if ErrorCode(err) == EINTERNAL {
+		return DefaultErrorMessage
+	}
+
+	for _, e := range chain(err) {
+		if e.Message != "" {
+			return e.Message
+		}
+	}
+
+	return DefaultErrorMessage
+}
+
+// ErrorDetail returns the first non-empty Error.Detail of the chain, if available.
+// For Errors the chain of the dominant error is used.
+func ErrorDetail(err error) string {
+	for _, e := range chain(err) {
+		if e.Detail != "" {
+			return e.Detail
+		}
+	}
+
+	return ""
+}
+
+// DetailMessage returns the message of the err for developers:
+// the first non-empty Error.Detail of the chain, see ErrorDetail, otherwise err.Error().
+func DetailMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	if detail := ErrorDetail(err); detail != "" {
+		return detail
+	}
+
+	return err.Error()
+}
+
+// ErrorFields returns fields of the error chain, inner fields override outer ones.
+// For Errors fields of all errors are merged.
 func ErrorFields(err error) map[string]interface{} {
 	if err == nil {
 		return nil
 	}
 	fields := map[string]interface{}{}
-	target := &Error{}
-	if errors.As(err, &target) {
+	target, errs := find(err)
+	for _, e := range errs {
+		if f := ErrorFields(e); len(f) > 0 {
+			copyMapTo(f, fields)
+		}
+	}
+	if target != nil {
 		if len(target.Fields) > 0 {
 			copyMapTo(target.Fields, fields)
 		}
@@ -165,14 +248,21 @@ func ErrorFields(err error) map[string]interface{} {
 	return fields
 }
 
+// ErrorTrace returns ops of the error chain.
+// For Errors the trace of the dominant error is returned, see ErrorTraces.
 func ErrorTrace(err error) []string {
 	if err == nil {
 		return nil
 	}
 
 	trace := []string{}
-	target := &Error{}
-	if errors.As(err, &target) {
+	target, errs := find(err)
+	if errs != nil {
+		if dominant := errs.dominant(); dominant != nil {
+			trace = append(trace, ErrorTrace(dominant)...)
+		}
+	}
+	if target != nil {
 		if target.Op != "" {
 			trace = append(trace, target.Op)
 		}
@@ -184,68 +274,140 @@ func ErrorTrace(err error) []string {
 	return trace
 }
 
-// ErrorWithCode adds an error code to the provided error.
-// If Error.Op is undefined, caller function name will be used as Op
-// If the err is an Error && err.Code is undefined, the code is applied to the Error;
-// If the err is an Error && err.Code is defined, the err is wrapped and the code is applied;
-// If the err is a regular error, the error is wrapped and the code is applied.
-func ErrorWithCode(err error, code errorCode) error {
-	target := &Error{}
-	if !errors.As(err, &target) {
-		return &Error{
-			Op:   CallerFunctionName(),
-			Err:  err,
-			Code: code,
-		}
+// ErrorTraces returns ops of the error chain, one trace per each branch of Errors.
+func ErrorTraces(err error) [][]string {
+	if err == nil {
+		return nil
 	}
 
-	if target.Code == "" {
-		if target.Op == "" {
-			target.Op = CallerFunctionName()
+	target, errs := find(err)
+	if errs != nil {
+		traces := [][]string{}
+		for _, e := range errs {
+			traces = append(traces, ErrorTraces(e)...)
 		}
+		return traces
+	}
 
-		target.Code = code
+	op := []string{}
+	if target == nil {
+		return [][]string{op}
+	}
+	if target.Op != "" {
+		op = append(op, target.Op)
+	}
 
-		return target
+	inner := ErrorTraces(target.Err)
+	if len(inner) == 0 {
+		return [][]string{op}
 	}
 
-	return &Error{
-		Op:   CallerFunctionName(),
-		Err:  err,
-		Code: code,
+	traces := make([][]string, 0, len(inner))
+	for _, trace := range inner {
+		traces = append(traces, append(append([]string{}, op...), trace...))
 	}
+
+	return traces
 }
 
-func OpError(op string, err error) error {
+// find returns the first *Error or Errors of the err chain.
+func find(err error) (*Error, Errors) {
+	for cur := err; cur != nil; cur = errors.Unwrap(cur) {
+		switch e := cur.(type) {
+		case *Error:
+			return e, nil
+		case Errors:
+			return nil, e
+		}
+	}
+
 	target := &Error{}
-	if !errors.As(err, &target) {
-		return &Error{
-			Op:  op,
-			Err: err,
+	if errors.As(err, &target) {
+		return target, nil
+	}
+
+	return nil, nil
+}
+
+// chain returns *Error of the err chain, for Errors the chain of the dominant error is used.
+func chain(err error) []*Error {
+	var res []*Error
+	for err != nil {
+		target, errs := find(err)
+		if errs != nil {
+			err = errs.dominant()
+			continue
 		}
+		if target == nil {
+			break
+		}
+		res = append(res, target)
+		err = target.Err
+	}
+
+	return res
+}
+
+// ErrorWithCode wraps the err with the code.
+// Caller function name is used as Op, unless the err is an Error with Op and without Code.
+// The err is not modified, because it may be a shared sentinel.
+func ErrorWithCode(err error, code Code) error {
+	return errorWithCode(err, code, CallerFunctionName(), newStack(err))
+}
+
+func errorWithCode(err error, code Code, op string, stack Stack) *Error {
+	target := &Error{}
+	if errors.As(err, &target) && target.Op != "" && target.Code == "" {
+		op = ""
 	}
 
-	if target.Op == "" {
-		target.Op = op
-		return target
+	return &Error{
+		Op:    op,
+		Err:   err,
+		Code:  code,
+		Stack: stack,
 	}
+}
 
+// NewError returns an Error with the code and the message,
+// caller function name is used as Op.
+func NewError(code Code, message string) error {
 	return &Error{
-		Op:  op,
-		Err: err,
+		Op:      CallerFunctionName(),
+		Code:    code,
+		Message: message,
+		Stack:   newStack(nil),
 	}
 }
 
+func OpError(op string, err error) error {
+	return opError(op, err, newStack(err))
+}
+
 func OpErrorOrNil(op string, err error) error {
 	if err == nil {
 		return nil
 	}
 
-	return OpError(op, err)
+	return opError(op, err, newStack(err))
+}
+
+func opError(op string, err error, stack Stack) *Error {
+	return &Error{
+		Op:    op,
+		Err:   err,
+		Stack: stack,
+	}
 }
 
 func copyMapTo(src, dst map[string]interface{}) {
 	for k, v := range src {
+		if k == ViolationsKey {
+			if merged, ok := mergeViolations(dst[k], v); ok {
+				dst[k] = merged
+				continue
+			}
+		}
 		dst[k] = v
 	}
 }
@@ -66,7 +66,7 @@ func TestAs(t *testing.T) {
 func TestErrorCode(t *testing.T) {
 	tests := []struct {
 		err  error
-		want errorCode
+		want Code
 	}{
 		{
 			err:  &Error{Code: ETEST},
@@ -92,6 +92,10 @@ func TestErrorCode(t *testing.T) {
 			err:  &Error{Err: &Error{}},
 			want: EINTERNAL,
 		},
+		{
+			err:  Errors{},
+			want: EINTERNAL,
+		},
 		{
 			err:  nil,
 			want: "",
@@ -155,6 +159,86 @@ func TestErrorMessage(t *testing.T) {
 	}
 }
 
+func TestPublicMessage(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{
+			err:  nil,
+			want: "",
+		},
+		{
+			err:  &Error{Code: ENOTFOUND, Message: "user not found", Detail: "select returned no rows"},
+			want: "user not found",
+		},
+		{
+			err:  OpError("outer", &Error{Code: ENOTFOUND, Message: "user not found"}),
+			want: "user not found",
+		},
+		{
+			err:  &Error{Code: ENOTFOUND, Message: "outer", Err: &Error{Message: "inner"}},
+			want: "outer",
+		},
+		{
+			err:  &Error{Code: EINTERNAL, Message: "db is down"},
+			want: DefaultErrorMessage,
+		},
+		{
+			err:  ErrorWithCode(errors.New("connection refused"), ECONFLICT),
+			want: DefaultErrorMessage,
+		},
+		{
+			err:  errors.New("connection refused"),
+			want: DefaultErrorMessage,
+		},
+		{
+			err:  Join(&Error{Code: EINVALID, Message: "invalid"}, &Error{Code: ENOTFOUND, Message: "not found"}),
+			want: "not found",
+		},
+	}
+
+	for i, tt := range tests {
+		if got := PublicMessage(tt.err); got != tt.want {
+			t.Fatalf("%d. PublicMessage want: %s, got: %s", i, tt.want, got)
+		}
+	}
+}
+
+func TestDetailMessage(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{
+			err:  nil,
+			want: "",
+		},
+		{
+			err:  &Error{Code: ENOTFOUND, Message: "user not found", Detail: "select returned no rows"},
+			want: "select returned no rows",
+		},
+		{
+			err:  OpError("outer", &Error{Op: "inner", Detail: "inner detail", Err: &Error{Detail: "innermost detail"}}),
+			want: "inner detail",
+		},
+		{
+			err:  OpError("outer", &Error{Op: "inner", Code: ENOTFOUND, Message: "user not found"}),
+			want: "outer: inner: <not_found> user not found",
+		},
+		{
+			err:  errors.New("connection refused"),
+			want: "connection refused",
+		},
+	}
+
+	for i, tt := range tests {
+		if got := DetailMessage(tt.err); got != tt.want {
+			t.Fatalf("%d. DetailMessage want: %s, got: %s", i, tt.want, got)
+		}
+	}
+}
+
 func TestErrorFields(t *testing.T) {
 	tests := []struct {
 		err  error
@@ -227,10 +311,10 @@ func eq(a, b map[string]interface{}) bool {
 func TestErrorWithCode(t *testing.T) {
 	type args struct {
 		err  error
-		code errorCode
+		code Code
 	}
-	// If Error.Op is undefined, caller function name will be used as Op
-	// If the err is an Error && err.Code is defined, the err is wrapped and the code is applied;
+	// The err is always wrapped, caller function name is used as Op,
+	// unless the err is an Error with Op and without Code.
 	tests := []struct {
 		name string
 		want *Error
@@ -250,7 +334,7 @@ func TestErrorWithCode(t *testing.T) {
 				err:  &Error{Op: "test", Message: "test"},
 				code: ETEST,
 			},
-			want: &Error{Op: "test", Code: ETEST, Message: "test"},
+			want: &Error{Code: ETEST, Err: &Error{Op: "test", Message: "test"}},
 		},
 		{
 			name: "err is an Error && err.Code is undefined, op empty",
@@ -258,7 +342,7 @@ func TestErrorWithCode(t *testing.T) {
 				err:  &Error{Message: "test"},
 				code: ETEST,
 			},
-			want: &Error{Op: "github.com/MrEhbr/app.errWithCode", Code: ETEST, Message: "test"},
+			want: &Error{Op: "github.com/MrEhbr/app.errWithCode", Code: ETEST, Err: &Error{Message: "test"}},
 		},
 		{
 			name: "err is an Error && err.Code is defined",
@@ -289,11 +373,34 @@ func TestErrorWithCode(t *testing.T) {
 			if got.Op != tt.want.Op {
 				t.Fatalf("%s. Op want: %s, got: %s", tt.name, tt.want.Op, got.Op)
 			}
+
+			if got.Err != tt.args.err {
+				t.Fatalf("%s. err is not wrapped", tt.name)
+			}
 		})
 	}
 }
 
-func errWithCode(err error, code errorCode) error {
+func TestErrorWithCode_sentinel(t *testing.T) {
+	sentinel := &Error{Message: "not found"}
+
+	coded := ErrorWithCode(sentinel, ENOTFOUND)
+	wrapped := OpError("users.Get", sentinel)
+
+	if sentinel.Op != "" || sentinel.Code != "" {
+		t.Fatalf("sentinel modified: %#v", sentinel)
+	}
+
+	if got := ErrorCode(coded); got != ENOTFOUND {
+		t.Fatalf("code want: %s, got: %s", ENOTFOUND, got)
+	}
+
+	if got := ErrorTrace(wrapped); len(got) != 1 || got[0] != "users.Get" {
+		t.Fatalf("trace want: [users.Get], got: %v", got)
+	}
+}
+
+func errWithCode(err error, code Code) error {
 	return ErrorWithCode(err, code)
 }
 
@@ -398,7 +505,7 @@ func baz() error {
 	return OpError("baz", bar())
 }
 
-func ExampleTrace() {
+func ExampleErrorTrace() {
 	const op = "ExampleRun"
 	err := baz()
 	fmt.Println(ErrorTrace(OpError(op, err)))
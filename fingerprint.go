@@ -0,0 +1,84 @@
+package app
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"strings"
+)
+
+// FingerprintOption configures Fingerprint.
+type FingerprintOption func(*fingerprintOptions)
+
+type fingerprintOptions struct {
+	stack bool
+}
+
+// FingerprintWithStack adds function names of the captured stack to the fingerprint.
+// Files and lines are ignored, so the fingerprint survives unrelated changes of the code.
+func FingerprintWithStack() FingerprintOption {
+	return func(o *fingerprintOptions) {
+		o.stack = true
+	}
+}
+
+// Fingerprint returns a stable identity of the err to group the same errors,
+// e.g. for dashboards and alert deduplication.
+// The fingerprint is a hex encoded hash of the code chain and ErrorTrace,
+// messages and field values are ignored. If Error.GroupKey is set in the chain,
+// the first one is used instead. Returns an empty string if the err is nil.
+func Fingerprint(err error, opts ...FingerprintOption) string {
+	if err == nil {
+		return ""
+	}
+
+	var o fingerprintOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	h := sha256.New()
+	var codes []string
+	for _, e := range chain(err) {
+		if e.GroupKey != "" {
+			writeSection(h, "group", []string{e.GroupKey})
+			return sum(h)
+		}
+		if e.Code != "" {
+			codes = append(codes, e.Code.String())
+		}
+	}
+	if len(codes) == 0 {
+		codes = append(codes, ErrorCode(err).String())
+	}
+
+	writeSection(h, "codes", codes)
+	writeSection(h, "trace", ErrorTrace(err))
+	if o.stack {
+		var funcs []string
+		for _, frame := range ErrorStack(err).Frames() {
+			// runtime frames depend on the Go version
+			if !strings.HasPrefix(frame.Function, "runtime.") {
+				funcs = append(funcs, frame.Function)
+			}
+		}
+		writeSection(h, "stack", funcs)
+	}
+
+	return sum(h)
+}
+
+func sum(h hash.Hash) string {
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// writeSection writes the named values separated by zero bytes, so different splits of the same text don't collide.
+func writeSection(w io.Writer, name string, values []string) {
+	_, _ = w.Write([]byte(name))
+	for _, v := range values {
+		_, _ = w.Write([]byte{0})
+		_, _ = w.Write([]byte(v))
+	}
+	_, _ = w.Write([]byte{0, 0})
+}
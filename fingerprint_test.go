@@ -0,0 +1,119 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestFingerprint(t *testing.T) {
+	base := &Error{Op: "GetUser", Code: ENOTFOUND, Message: "user 1 not found", Fields: map[string]interface{}{"id": 1}}
+
+	tests := []struct {
+		name string
+		err  error
+		same bool
+	}{
+		{
+			name: "other message and fields",
+			err:  &Error{Op: "GetUser", Code: ENOTFOUND, Message: "user 2 not found", Fields: map[string]interface{}{"id": 2}},
+			same: true,
+		},
+		{
+			name: "wrapped with foreign error",
+			err:  fmt.Errorf("wrapped: %w", base),
+			same: true,
+		},
+		{
+			name: "other code",
+			err:  &Error{Op: "GetUser", Code: EINVALID},
+			same: false,
+		},
+		{
+			name: "other op",
+			err:  &Error{Op: "GetOrder", Code: ENOTFOUND},
+			same: false,
+		},
+		{
+			name: "wrapped with op",
+			err:  OpError("Handler", base),
+			same: false,
+		},
+		{
+			name: "group key",
+			err:  &Error{Op: "GetUser", Code: ENOTFOUND, GroupKey: "users"},
+			same: false,
+		},
+	}
+
+	want := Fingerprint(base)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Fingerprint(tt.err)
+			if (got == want) != tt.same {
+				t.Fatalf("Fingerprint() want same: %t, got: %s, base: %s", tt.same, got, want)
+			}
+		})
+	}
+}
+
+func TestFingerprint_stable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			want: "",
+		},
+		{
+			name: "foreign",
+			err:  errors.New("foo"),
+			want: "641d8e76ba57ef05",
+		},
+		{
+			name: "chain",
+			err:  &Error{Op: "outer", Code: ECONFLICT, Err: &Error{Op: "inner", Code: ENOTFOUND}},
+			want: "acb062690e09e46a",
+		},
+		{
+			name: "group key overrides chain",
+			err:  &Error{Op: "outer", Code: ECONFLICT, Err: &Error{Op: "inner", Code: ENOTFOUND, GroupKey: "users"}},
+			want: "fb5567870641b7d6",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Fingerprint(tt.err); got != tt.want {
+				t.Fatalf("Fingerprint() want: %s, got: %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func newFingerprintError() error { return ErrorWithCode(errors.New("foo"), ETEST) }
+
+func fingerprintFoo() error { return newFingerprintError() }
+
+func fingerprintBar() error { return newFingerprintError() }
+
+func TestFingerprintWithStack(t *testing.T) {
+	EnableStackCapture(true)
+	defer EnableStackCapture(false)
+
+	foo, bar := fingerprintFoo(), fingerprintBar()
+	if Fingerprint(OpError("op", foo)) != Fingerprint(OpError("op", bar)) {
+		t.Fatal("Fingerprint() want same without stack")
+	}
+
+	if Fingerprint(OpError("op", foo), FingerprintWithStack()) == Fingerprint(OpError("op", bar), FingerprintWithStack()) {
+		t.Fatal("Fingerprint() want different with stack")
+	}
+
+	if Fingerprint(foo, FingerprintWithStack()) != Fingerprint(fingerprintFoo(), FingerprintWithStack()) {
+		t.Fatal("Fingerprint() want same for the same stack")
+	}
+}
@@ -0,0 +1,115 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Format implements fmt.Formatter.
+//
+// %s, %v print the same as Error()
+// %q prints quoted Error()
+// %+v prints the whole chain one layer per line in form of "op: <code> message (detail) {key=value}", sensitive values are redacted,
+// wrapped non-app errors are printed by their message, the captured stack is printed last.
+func (e *Error) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			_, _ = io.WriteString(s, e.verbose())
+			return
+		}
+		_, _ = io.WriteString(s, e.Error())
+	case 's':
+		_, _ = io.WriteString(s, e.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	default:
+		fmt.Fprintf(s, "%%!%c(*app.Error=%s)", verb, e.Error())
+	}
+}
+
+func (e *Error) verbose() string {
+	lines := []string{e.layer()}
+	err := e.Err
+	for err != nil {
+		if target, ok := err.(*Error); ok {
+			lines = append(lines, target.layer())
+			err = target.Err
+			continue
+		}
+
+		if errs, ok := err.(Errors); ok {
+			lines = append(lines, errs.verbose())
+			break
+		}
+
+		var inner error
+		target, errs := find(err)
+		switch {
+		case errs != nil:
+			inner = errs
+		case target != nil:
+			inner = target
+		default:
+			lines = append(lines, err.Error())
+		}
+		if inner == nil {
+			break
+		}
+
+		// print only the wrapper part of the message
+		wrapper := strings.TrimSuffix(strings.TrimSuffix(err.Error(), inner.Error()), ": ")
+		if wrapper != "" {
+			lines = append(lines, wrapper)
+		}
+		err = inner
+	}
+
+	if stack := ErrorStack(e); len(stack) > 0 {
+		lines = append(lines, stack.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// layer returns the representation of the error without wrapped error.
+func (e *Error) layer() string {
+	parts := make([]string, 0, 4)
+	if e.Code != "" {
+		parts = append(parts, "<"+e.Code.String()+">")
+	}
+	if e.Message != "" {
+		parts = append(parts, e.Message)
+	}
+	if e.Detail != "" {
+		parts = append(parts, "("+e.Detail+")")
+	}
+	if len(e.Fields) > 0 {
+		redacted := redactPolicy.Redact(e.Fields)
+		keys := make([]string, 0, len(redacted))
+		for k := range redacted {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		fields := make([]string, 0, len(keys))
+		for _, k := range keys {
+			fields = append(fields, fmt.Sprintf("%s=%v", k, redacted[k]))
+		}
+		parts = append(parts, "{"+strings.Join(fields, " ")+"}")
+	}
+
+	rest := strings.Join(parts, " ")
+	switch {
+	case e.Op == "":
+		return rest
+	case rest == "":
+		return e.Op
+	default:
+		return e.Op + ": " + rest
+	}
+}
+
+var _ fmt.Formatter = &Error{}
@@ -0,0 +1,87 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestError_Format(t *testing.T) {
+	err := &Error{
+		Op:      "outer",
+		Code:    EINVALID,
+		Message: "invalid request",
+		Err: fmt.Errorf("decoding: %w", &Error{
+			Op:      "inner",
+			Code:    ECANNOTDECODE,
+			Message: "bad json",
+			Detail:  "unexpected end of input",
+			Fields:  map[string]interface{}{"offset": 10, "field": "name"},
+			Err:     errors.New("unexpected EOF"),
+		}),
+	}
+
+	tests := []struct {
+		format string
+		err    error
+		want   string
+	}{
+		{
+			format: "%v",
+			err:    err,
+			want:   "outer: decoding: inner: unexpected EOF",
+		},
+		{
+			format: "%s",
+			err:    err,
+			want:   "outer: decoding: inner: unexpected EOF",
+		},
+		{
+			format: "%q",
+			err:    err,
+			want:   `"outer: decoding: inner: unexpected EOF"`,
+		},
+		{
+			format: "%+v",
+			err:    err,
+			want: strings.Join([]string{
+				"outer: <invalid> invalid request",
+				"decoding",
+				"inner: <cannot_decode> bad json (unexpected end of input) {field=name offset=10}",
+				"unexpected EOF",
+			}, "\n"),
+		},
+		{
+			format: "%+v",
+			err:    OpError("foo", &Error{Op: "bar", Code: ENOTFOUND}),
+			want:   "foo\nbar: <not_found>",
+		},
+		{
+			format: "%+v",
+			err:    &Error{},
+			want:   "",
+		},
+		{
+			format: "%d",
+			err:    &Error{Code: ETEST},
+			want:   "%!d(*app.Error=<test_error_code>)",
+		},
+	}
+
+	for i, tt := range tests {
+		if got := fmt.Sprintf(tt.format, tt.err); got != tt.want {
+			t.Fatalf("%d. %s want:\n%s\ngot:\n%s", i, tt.format, tt.want, got)
+		}
+	}
+}
+
+func TestError_Format_stack(t *testing.T) {
+	withStackCapture(t)
+
+	got := fmt.Sprintf("%+v", stackFunc())
+	want := "github.com/MrEhbr/app.stackFunc: <test_error_code>\ntest\ngithub.com/MrEhbr/app.stackFunc\n\t"
+	if !strings.HasPrefix(got, want) {
+		t.Fatalf("want prefix:\n%s\ngot:\n%s", want, got)
+	}
+}
@@ -0,0 +1,21 @@
+module github.com/MrEhbr/app/grpc
+
+go 1.19
+
+require (
+	github.com/MrEhbr/app v1.0.0
+	github.com/google/go-cmp v0.6.0
+	google.golang.org/grpc v1.56.3
+	google.golang.org/protobuf v1.30.0
+)
+
+require (
+	github.com/golang/protobuf v1.5.3 // indirect
+	golang.org/x/net v0.9.0 // indirect
+	golang.org/x/sys v0.23.0 // indirect
+	golang.org/x/text v0.9.0 // indirect
+	google.golang.org/genproto v0.0.0-20230410155749-daa745c078e1 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/MrEhbr/app => ../
@@ -0,0 +1,95 @@
+package grpc
+
+import (
+	"context"
+	"io"
+
+	"github.com/MrEhbr/app"
+	"google.golang.org/grpc"
+)
+
+// UnaryServerInterceptor returns interceptor that converts handler errors to gRPC status.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, ToStatus(err).Err()
+		}
+
+		return resp, nil
+	}
+}
+
+// StreamServerInterceptor returns interceptor that converts handler errors to gRPC status.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := handler(srv, ss); err != nil {
+			return ToStatus(err).Err()
+		}
+
+		return nil
+	}
+}
+
+// UnaryClientInterceptor returns interceptor that converts gRPC status errors to *app.Error.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return FromError(invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
+
+// StreamClientInterceptor returns interceptor that converts gRPC status errors to *app.Error.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, FromError(err)
+		}
+
+		return &clientStream{ClientStream: cs}, nil
+	}
+}
+
+type clientStream struct {
+	grpc.ClientStream
+}
+
+func (s *clientStream) SendMsg(m interface{}) error {
+	return fromStreamError(s.ClientStream.SendMsg(m))
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	return fromStreamError(s.ClientStream.RecvMsg(m))
+}
+
+func (s *clientStream) CloseSend() error {
+	return fromStreamError(s.ClientStream.CloseSend())
+}
+
+func fromStreamError(err error) error {
+	if err == io.EOF {
+		return err
+	}
+
+	return FromError(err)
+}
+
+// UnaryServerRecoveryInterceptor returns interceptor that recovers panics of handlers with app.Recover.
+// Chain it after UnaryServerInterceptor to convert recovered errors to gRPC status.
+func UnaryServerRecoveryInterceptor(opts ...app.RecoverOption) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer app.Recover(&err, opts...)
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerRecoveryInterceptor returns interceptor that recovers panics of handlers with app.Recover.
+// Chain it after StreamServerInterceptor to convert recovered errors to gRPC status.
+func StreamServerRecoveryInterceptor(opts ...app.RecoverOption) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer app.Recover(&err, opts...)
+
+		return handler(srv, ss)
+	}
+}
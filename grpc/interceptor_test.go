@@ -0,0 +1,160 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/MrEhbr/app"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type healthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+	err   error
+	panic interface{}
+}
+
+func (s *healthServer) Check(context.Context, *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	if s.panic != nil {
+		panic(s.panic)
+	}
+	return nil, s.err
+}
+
+func (s *healthServer) Watch(_ *grpc_health_v1.HealthCheckRequest, stream grpc_health_v1.Health_WatchServer) error {
+	if s.panic != nil {
+		panic(s.panic)
+	}
+	if err := stream.Send(&grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}); err != nil {
+		return err
+	}
+
+	return s.err
+}
+
+func dial(t *testing.T, err error) grpc_health_v1.HealthClient {
+	t.Helper()
+
+	return dialServer(t, &healthServer{err: err})
+}
+
+func dialServer(t *testing.T, hs *healthServer) grpc_health_v1.HealthClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(UnaryServerInterceptor(), UnaryServerRecoveryInterceptor()),
+		grpc.ChainStreamInterceptor(StreamServerInterceptor(), StreamServerRecoveryInterceptor()),
+	)
+	grpc_health_v1.RegisterHealthServer(srv, hs)
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor()),
+		grpc.WithStreamInterceptor(StreamClientInterceptor()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return grpc_health_v1.NewHealthClient(conn)
+}
+
+func assertAppError(t *testing.T, want, got error) {
+	t.Helper()
+
+	if !errors.Is(got, &app.Error{}) {
+		t.Fatalf("want *app.Error, got: %T", got)
+	}
+
+	if app.ErrorCode(want) != app.ErrorCode(got) {
+		t.Fatalf("code want: %s, got: %s", app.ErrorCode(want), app.ErrorCode(got))
+	}
+
+	if app.ErrorMessage(want) != app.ErrorMessage(got) {
+		t.Fatalf("message want: %s, got: %s", app.ErrorMessage(want), app.ErrorMessage(got))
+	}
+
+	if diff := cmp.Diff(app.ErrorTrace(want), app.ErrorTrace(got)); diff != "" {
+		t.Fatalf("ErrorTrace() mismatch (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(app.ErrorFields(want), app.ErrorFields(got)); diff != "" {
+		t.Fatalf("ErrorFields() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestUnaryInterceptors(t *testing.T) {
+	want := app.OpError("Check", &app.Error{
+		Code:    app.EPERMISSIONDENIED,
+		Message: "access denied",
+		Fields:  map[string]interface{}{"user": "42"},
+	})
+	client := dial(t, want)
+
+	_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	assertAppError(t, want, err)
+}
+
+func TestStreamInterceptors(t *testing.T) {
+	want := &app.Error{Op: "Watch", Code: app.ENOTFOUND, Message: "service not found"}
+	client := dial(t, want)
+
+	stream, err := client.Watch(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = stream.Recv()
+	assertAppError(t, want, err)
+}
+
+func TestUnaryServerRecoveryInterceptor(t *testing.T) {
+	client := dialServer(t, &healthServer{panic: "boom"})
+
+	_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	if code := app.ErrorCode(err); code != app.EBEHAVIOUR {
+		t.Fatalf("code want: %s, got: %s", app.EBEHAVIOUR, code)
+	}
+
+	wantTrace := []string{"github.com/MrEhbr/app/grpc.(*healthServer).Check"}
+	if diff := cmp.Diff(wantTrace, app.ErrorTrace(err)); diff != "" {
+		t.Fatalf("ErrorTrace() mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, ok := app.ErrorFields(err)[app.PanicKey]; ok {
+		t.Fatalf("panic value is sent to the client: %v", app.ErrorFields(err))
+	}
+}
+
+func TestStreamServerRecoveryInterceptor(t *testing.T) {
+	client := dialServer(t, &healthServer{panic: "boom"})
+
+	stream, err := client.Watch(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = stream.Recv()
+	if code := app.ErrorCode(err); code != app.EBEHAVIOUR {
+		t.Fatalf("code want: %s, got: %s", app.EBEHAVIOUR, code)
+	}
+}
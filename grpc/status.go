@@ -0,0 +1,220 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/MrEhbr/app"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Code returns the gRPC code of the err registered in app.CodeInfo.
+// Returns codes.Unknown if the code is unregistered or has no gRPC code.
+func Code(err error) codes.Code {
+	if err == nil {
+		return codes.OK
+	}
+
+	if info, ok := app.LookupCode(app.ErrorCode(err)); ok && info.GRPCCode != 0 {
+		return codes.Code(info.GRPCCode)
+	}
+
+	return codes.Unknown
+}
+
+// ToStatus converts the err to gRPC status.
+// Error code, public message, trace and fields are carried in status details,
+// so they can be restored with FromStatus on the other side.
+func ToStatus(err error) *status.Status {
+	if err == nil {
+		return status.New(codes.OK, "")
+	}
+
+	if !errors.Is(err, &app.Error{}) {
+		if st, ok := status.FromError(err); ok {
+			return st
+		}
+
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return status.FromContextError(err)
+		}
+	}
+
+	// internal messages are not sent to clients
+	message := app.PublicMessage(err)
+	st := status.New(Code(err), message)
+	if message == app.DefaultErrorMessage {
+		message = ""
+	}
+
+	fields := toStruct(app.RedactedErrorFields(err))
+	// the panic value is an internal detail
+	delete(fields, app.PanicKey)
+
+	details, derr := structpb.NewStruct(map[string]interface{}{
+		"code":    app.ErrorCode(err).String(),
+		"message": message,
+		"trace":   toList(app.ErrorTrace(err)),
+		"fields":  fields,
+	})
+	if derr != nil {
+		return st
+	}
+
+	if withDetails, derr := st.WithDetails(details); derr == nil {
+		return withDetails
+	}
+
+	return st
+}
+
+// FromStatus converts the gRPC status to the *app.Error.
+// Returns nil if status is nil or has codes.OK.
+func FromStatus(st *status.Status) error {
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+
+	for _, detail := range st.Details() {
+		s, ok := detail.(*structpb.Struct)
+		if !ok {
+			continue
+		}
+
+		values := s.AsMap()
+		code, ok := values["code"].(string)
+		if !ok {
+			continue
+		}
+
+		e := &app.Error{Code: app.Code(code)}
+		if e.Code == "" {
+			e.Code = fromCode(st.Code())
+		}
+
+		e.Message, _ = values["message"].(string)
+		if fields, ok := values["fields"].(map[string]interface{}); ok && len(fields) > 0 {
+			if raw, ok := fields[app.ViolationsKey]; ok {
+				if violations, err := unmarshalViolations(raw); err == nil {
+					fields[app.ViolationsKey] = violations
+				}
+			}
+			e.Fields = fields
+		}
+
+		if trace, ok := values["trace"].([]interface{}); ok {
+			cur := e
+			for i, op := range trace {
+				if i > 0 {
+					next := &app.Error{}
+					cur.Err = next
+					cur = next
+				}
+				cur.Op, _ = op.(string)
+			}
+		}
+
+		return e
+	}
+
+	return &app.Error{Code: fromCode(st.Code()), Message: st.Message()}
+}
+
+// FromError converts the gRPC status error to the *app.Error.
+// If err is not a gRPC status error it's returned as is.
+func FromError(err error) error {
+	if err == nil || errors.Is(err, &app.Error{}) {
+		return err
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	return FromStatus(st)
+}
+
+func fromCode(c codes.Code) app.Code {
+	switch c {
+	case codes.Aborted, codes.FailedPrecondition:
+		return app.ECONFLICT
+	case codes.InvalidArgument, codes.OutOfRange:
+		return app.EINVALID
+	case codes.NotFound:
+		return app.ENOTFOUND
+	case codes.AlreadyExists:
+		return app.EALREADYEXISTS
+	case codes.PermissionDenied:
+		return app.EPERMISSIONDENIED
+	case codes.Unauthenticated:
+		return app.EUNAUTHENTICATED
+	case codes.Unimplemented:
+		return app.EUNSUPPORTED
+	default:
+		return app.EINTERNAL
+	}
+}
+
+func toList(s []string) []interface{} {
+	res := make([]interface{}, 0, len(s))
+	for _, v := range s {
+		res = append(res, v)
+	}
+
+	return res
+}
+
+// toStruct converts fields to values supported by structpb,
+// violations are converted as they are marshaled to JSON, so they can be restored with unmarshalViolations,
+// other unsupported values are converted to strings.
+func toStruct(fields map[string]interface{}) map[string]interface{} {
+	res := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		if violations, ok := v.([]app.Violation); ok {
+			if list, err := marshalViolations(violations); err == nil {
+				res[k] = list
+				continue
+			}
+		}
+
+		if _, err := structpb.NewValue(v); err != nil {
+			v = fmt.Sprint(v)
+		}
+		res[k] = v
+	}
+
+	return res
+}
+
+func marshalViolations(violations []app.Violation) (interface{}, error) {
+	data, err := json.Marshal(violations)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []interface{}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func unmarshalViolations(v interface{}) ([]app.Violation, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []app.Violation
+	if err := json.Unmarshal(data, &violations); err != nil {
+		return nil, err
+	}
+
+	return violations, nil
+}
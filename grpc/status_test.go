@@ -0,0 +1,212 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/MrEhbr/app"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestToStatus(t *testing.T) {
+	tests := []struct {
+		err         error
+		wantCode    codes.Code
+		wantMessage string
+	}{
+		{
+			err:      nil,
+			wantCode: codes.OK,
+		},
+		{
+			err:         errors.New("secret"),
+			wantCode:    codes.Internal,
+			wantMessage: app.DefaultErrorMessage,
+		},
+		{
+			err:         status.Error(codes.Unavailable, "unavailable"),
+			wantCode:    codes.Unavailable,
+			wantMessage: "unavailable",
+		},
+		{
+			err:         fmt.Errorf("%w", context.DeadlineExceeded),
+			wantCode:    codes.DeadlineExceeded,
+			wantMessage: context.DeadlineExceeded.Error(),
+		},
+		{
+			err:         &app.Error{Code: app.ENOTFOUND, Message: "user not found"},
+			wantCode:    codes.NotFound,
+			wantMessage: "user not found",
+		},
+		{
+			err:         &app.Error{Code: app.EINTERNAL, Message: "db is down"},
+			wantCode:    codes.Internal,
+			wantMessage: app.DefaultErrorMessage,
+		},
+		{
+			err:         app.OpError("test", &app.Error{Code: app.EUNAUTHENTICATED, Message: "bad token"}),
+			wantCode:    codes.Unauthenticated,
+			wantMessage: "bad token",
+		},
+	}
+
+	for i, tt := range tests {
+		st := ToStatus(tt.err)
+		if st.Code() != tt.wantCode {
+			t.Fatalf("%d. code want: %s, got: %s", i, tt.wantCode, st.Code())
+		}
+
+		if st.Message() != tt.wantMessage {
+			t.Fatalf("%d. message want: %s, got: %s", i, tt.wantMessage, st.Message())
+		}
+	}
+}
+
+func TestFromStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		st         *status.Status
+		wantCode   string
+		wantMsg    string
+		wantTrace  []string
+		wantFields map[string]interface{}
+	}{
+		{
+			name:       "app error",
+			st:         ToStatus(app.OpError("outer", &app.Error{Op: "inner", Code: app.ECONFLICT, Message: "version mismatch", Fields: map[string]interface{}{"version": "2"}})),
+			wantCode:   app.ECONFLICT.String(),
+			wantMsg:    "version mismatch",
+			wantTrace:  []string{"outer", "inner"},
+			wantFields: map[string]interface{}{"version": "2"},
+		},
+		{
+			name:       "code without gRPC counterpart",
+			st:         ToStatus(&app.Error{Code: app.ENOTMODIFIED, Fields: map[string]interface{}{"id": struct{}{}}}),
+			wantCode:   app.ENOTMODIFIED.String(),
+			wantMsg:    app.DefaultErrorMessage,
+			wantTrace:  []string{},
+			wantFields: map[string]interface{}{"id": "{}"},
+		},
+		{
+			name: "violations",
+			st: ToStatus(&app.Error{Code: app.EINVALID, Message: "invalid user", Fields: map[string]interface{}{app.ViolationsKey: []app.Violation{
+				{Field: "name", Rule: "required", Message: "name is required"},
+				{Field: "nickname", Rule: "min_length", Value: "a", Message: "nickname is too short"},
+			}}}),
+			wantCode:  app.EINVALID.String(),
+			wantMsg:   "invalid user",
+			wantTrace: []string{},
+			wantFields: map[string]interface{}{app.ViolationsKey: []app.Violation{
+				{Field: "name", Rule: "required", Message: "name is required"},
+				{Field: "nickname", Rule: "min_length", Value: "a", Message: "nickname is too short"},
+			}},
+		},
+		{
+			name:       "internal message is hidden",
+			st:         ToStatus(&app.Error{Code: app.EINTERNAL, Message: "db is down"}),
+			wantCode:   app.EINTERNAL.String(),
+			wantMsg:    app.DefaultErrorMessage,
+			wantTrace:  []string{},
+			wantFields: map[string]interface{}{},
+		},
+		{
+			name:       "status without details",
+			st:         status.New(codes.InvalidArgument, "bad request"),
+			wantCode:   app.EINVALID.String(),
+			wantMsg:    "bad request",
+			wantTrace:  []string{},
+			wantFields: map[string]interface{}{},
+		},
+		{
+			name:       "unknown status",
+			st:         status.New(codes.DataLoss, "data loss"),
+			wantCode:   app.EINTERNAL.String(),
+			wantMsg:    "data loss",
+			wantTrace:  []string{},
+			wantFields: map[string]interface{}{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := FromStatus(tt.st)
+			if !errors.Is(err, &app.Error{}) {
+				t.Fatalf("want *app.Error, got: %T", err)
+			}
+
+			if got := app.ErrorCode(err).String(); got != tt.wantCode {
+				t.Fatalf("code want: %s, got: %s", tt.wantCode, got)
+			}
+
+			if got := app.ErrorMessage(err); got != tt.wantMsg {
+				t.Fatalf("message want: %s, got: %s", tt.wantMsg, got)
+			}
+
+			if diff := cmp.Diff(tt.wantTrace, app.ErrorTrace(err)); diff != "" {
+				t.Fatalf("ErrorTrace() mismatch (-want +got):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tt.wantFields, app.ErrorFields(err)); diff != "" {
+				t.Fatalf("ErrorFields() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		if err := FromStatus(status.New(codes.OK, "")); err != nil {
+			t.Fatalf("want nil, got: %v", err)
+		}
+	})
+}
+
+var registerCodeOnce sync.Once
+
+func TestCode(t *testing.T) {
+	const code app.Code = "resource_exhausted"
+	// the registry is global, so the code is registered once with -count=N
+	registerCodeOnce.Do(func() {
+		app.MustRegisterCode(code, app.CodeInfo{GRPCCode: uint32(codes.ResourceExhausted)})
+	})
+
+	tests := []struct {
+		err  error
+		want codes.Code
+	}{
+		{
+			err:  nil,
+			want: codes.OK,
+		},
+		{
+			err:  errors.New("foo"),
+			want: codes.Internal,
+		},
+		{
+			err:  &app.Error{Code: app.ECONFLICT},
+			want: codes.Aborted,
+		},
+		{
+			err:  &app.Error{Code: code},
+			want: codes.ResourceExhausted,
+		},
+		{
+			err:  &app.Error{Code: "unregistered"},
+			want: codes.Unknown,
+		},
+	}
+
+	for i, tt := range tests {
+		if got := Code(tt.err); got != tt.want {
+			t.Fatalf("%d. Code want: %s, got: %s", i, tt.want, got)
+		}
+	}
+
+	err := FromStatus(ToStatus(&app.Error{Code: code, Message: "quota exceeded"}))
+	if app.ErrorCode(err) != code {
+		t.Fatalf("custom code want: %s, got: %s", code, app.ErrorCode(err))
+	}
+}
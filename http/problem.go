@@ -0,0 +1,98 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/MrEhbr/app"
+)
+
+// ProblemContentType is the media type of RFC 7807 problem details.
+const ProblemContentType = "application/problem+json"
+
+// Problem defines RFC 7807 problem details.
+type Problem struct {
+	Type     string                 `json:"type"`
+	Title    string                 `json:"title"`
+	Status   int                    `json:"status"`
+	Detail   string                 `json:"detail,omitempty"`
+	Instance string                 `json:"instance,omitempty"`
+	Code     string                 `json:"code,omitempty"`
+	Fields   map[string]interface{} `json:"fields,omitempty"`
+}
+
+// ProblemWriter writes errors as problem details.
+type ProblemWriter struct {
+	// Statuses overrides HTTP status codes of error codes.
+	Statuses Statuses
+	// ExposeInternal exposes app.DetailMessage instead of app.PublicMessage, fields of server errors
+	// and the value of recovered panic, e.g. for development.
+	ExposeInternal bool
+}
+
+// Problem converts the err to problem details.
+func (pw ProblemWriter) Problem(err error) Problem {
+	status := pw.Statuses.Status(err)
+	code := app.ErrorCode(err)
+	problem := Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Code:   code.String(),
+		Detail: app.PublicMessage(err),
+		Fields: app.RedactedErrorFields(err),
+	}
+
+	if pw.ExposeInternal {
+		problem.Detail = app.DetailMessage(err)
+	} else if status >= http.StatusInternalServerError {
+		// fields of server errors are internal details
+		problem.Fields = nil
+	} else if _, ok := problem.Fields[app.PanicKey]; ok {
+		// the panic value is an internal detail
+		delete(problem.Fields, app.PanicKey)
+		if len(problem.Fields) == 0 {
+			problem.Fields = nil
+		}
+	}
+
+	return problem
+}
+
+// Write writes the err to w as problem details.
+// If the fields of the err can't be encoded, the problem details are written without them
+// and the error with ECANNOTENCODE code is returned.
+func (pw ProblemWriter) Write(w http.ResponseWriter, r *http.Request, err error) error {
+	problem := pw.Problem(err)
+	if r != nil && r.URL != nil {
+		problem.Instance = r.URL.Path
+	}
+
+	// 304 response must not contain a body
+	if problem.Status == http.StatusNotModified {
+		w.WriteHeader(problem.Status)
+		return nil
+	}
+
+	body, encodeErr := json.Marshal(problem)
+	if encodeErr != nil {
+		// fields are the only values that can fail to encode
+		problem.Fields = nil
+		body, _ = json.Marshal(problem)
+		encodeErr = app.ErrorWithCode(encodeErr, app.ECANNOTENCODE)
+	}
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(problem.Status)
+	if _, err := w.Write(body); err != nil {
+		return app.OpError(app.CurrentFunctionName(), err)
+	}
+
+	return encodeErr
+}
+
+// WriteProblem writes the err to w as problem details using the zero ProblemWriter.
+func WriteProblem(w http.ResponseWriter, r *http.Request, err error) error {
+	return ProblemWriter{}.Write(w, r, err)
+}
@@ -0,0 +1,155 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MrEhbr/app"
+)
+
+func TestProblemWriter_Write(t *testing.T) {
+	tests := []struct {
+		name     string
+		writer   ProblemWriter
+		err      error
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "not found",
+			err:      &app.Error{Code: app.ENOTFOUND, Message: "user not found", Fields: map[string]interface{}{"id": "42"}},
+			wantCode: http.StatusNotFound,
+			wantBody: `{"type":"about:blank","title":"Not Found","status":404,"detail":"user not found","instance":"/users/42","code":"not_found","fields":{"id":"42"}}`,
+		},
+		{
+			name:     "sensitive fields",
+			err:      &app.Error{Code: app.EINVALID, Message: "invalid password", Fields: map[string]interface{}{"password": "123"}},
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid password","instance":"/users/42","code":"invalid","fields":{"password":"[REDACTED]"}}`,
+		},
+		{
+			name:     "internal message is hidden",
+			err:      &app.Error{Code: app.EINTERNAL, Message: "db is down"},
+			wantCode: http.StatusInternalServerError,
+			wantBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"An internal error has occurred","instance":"/users/42","code":"internal"}`,
+		},
+		{
+			name:     "internal message is exposed",
+			writer:   ProblemWriter{ExposeInternal: true},
+			err:      &app.Error{Code: app.EINTERNAL, Message: "db is down"},
+			wantCode: http.StatusInternalServerError,
+			wantBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"\u003cinternal\u003e db is down","instance":"/users/42","code":"internal"}`,
+		},
+		{
+			name:     "internal fields are hidden",
+			err:      &app.Error{Code: app.EINTERNAL, Message: "query failed", Fields: map[string]interface{}{"query": "select * from users", "dsn_host": "10.0.0.1"}},
+			wantCode: http.StatusInternalServerError,
+			wantBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"An internal error has occurred","instance":"/users/42","code":"internal"}`,
+		},
+		{
+			name:     "internal fields are exposed",
+			writer:   ProblemWriter{ExposeInternal: true},
+			err:      &app.Error{Code: app.EINTERNAL, Message: "query failed", Fields: map[string]interface{}{"query": "select * from users", "dsn_host": "10.0.0.1"}},
+			wantCode: http.StatusInternalServerError,
+			wantBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"\u003cinternal\u003e query failed","instance":"/users/42","code":"internal","fields":{"dsn_host":"10.0.0.1","query":"select * from users"}}`,
+		},
+		{
+			name:     "detail is hidden",
+			err:      &app.Error{Code: app.ECONFLICT, Message: "order is already paid", Detail: "version mismatch: 2 != 3"},
+			wantCode: http.StatusConflict,
+			wantBody: `{"type":"about:blank","title":"Conflict","status":409,"detail":"order is already paid","instance":"/users/42","code":"conflict"}`,
+		},
+		{
+			name:     "detail is exposed",
+			writer:   ProblemWriter{ExposeInternal: true},
+			err:      &app.Error{Code: app.ECONFLICT, Message: "order is already paid", Detail: "version mismatch: 2 != 3"},
+			wantCode: http.StatusConflict,
+			wantBody: `{"type":"about:blank","title":"Conflict","status":409,"detail":"version mismatch: 2 != 3","instance":"/users/42","code":"conflict"}`,
+		},
+		{
+			name:     "panic is hidden",
+			err:      &app.Error{Code: app.EBEHAVIOUR, Detail: "panic: boom", Fields: map[string]interface{}{app.PanicKey: "boom", "id": "42"}},
+			wantCode: http.StatusInternalServerError,
+			wantBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"An internal error has occurred","instance":"/users/42","code":"undefined_behavior"}`,
+		},
+		{
+			name:     "panic is exposed",
+			writer:   ProblemWriter{ExposeInternal: true},
+			err:      &app.Error{Code: app.EBEHAVIOUR, Detail: "panic: boom", Fields: map[string]interface{}{app.PanicKey: "boom"}},
+			wantCode: http.StatusInternalServerError,
+			wantBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"panic: boom","instance":"/users/42","code":"undefined_behavior","fields":{"panic":"boom"}}`,
+		},
+		{
+			name:     "std error",
+			err:      errors.New("secret"),
+			wantCode: http.StatusInternalServerError,
+			wantBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"An internal error has occurred","instance":"/users/42","code":"internal"}`,
+		},
+		{
+			name:     "std error is exposed",
+			writer:   ProblemWriter{ExposeInternal: true},
+			err:      errors.New("db down"),
+			wantCode: http.StatusInternalServerError,
+			wantBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"db down","instance":"/users/42","code":"internal"}`,
+		},
+		{
+			name:     "custom statuses",
+			writer:   ProblemWriter{Statuses: Statuses{app.EINVALID: http.StatusUnprocessableEntity}},
+			err:      &app.Error{Code: app.EINVALID, Message: "invalid name"},
+			wantCode: http.StatusUnprocessableEntity,
+			wantBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"invalid name","instance":"/users/42","code":"invalid"}`,
+		},
+		{
+			name:     "not modified",
+			err:      &app.Error{Code: app.ENOTMODIFIED},
+			wantCode: http.StatusNotModified,
+			wantBody: ``,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+			if err := tt.writer.Write(rec, req, tt.err); err != nil {
+				t.Fatal(err)
+			}
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status want: %d, got: %d", tt.wantCode, rec.Code)
+			}
+
+			if got := rec.Body.String(); got != tt.wantBody {
+				t.Fatalf("body want: %s, got: %s", tt.wantBody, got)
+			}
+
+			if tt.wantBody == "" {
+				return
+			}
+
+			if got := rec.Header().Get("Content-Type"); got != ProblemContentType {
+				t.Fatalf("content type want: %s, got: %s", ProblemContentType, got)
+			}
+		})
+	}
+}
+
+func TestProblemWriter_Write_unencodableFields(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	err := &app.Error{Code: app.EINVALID, Message: "invalid name", Fields: map[string]interface{}{"ch": make(chan int)}}
+	if got := app.ErrorCode(ProblemWriter{}.Write(rec, req, err)); got != app.ECANNOTENCODE {
+		t.Fatalf("error code want: %s, got: %s", app.ECANNOTENCODE, got)
+	}
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status want: %d, got: %d", http.StatusBadRequest, rec.Code)
+	}
+
+	wantBody := `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid name","instance":"/users/42","code":"invalid"}`
+	if got := rec.Body.String(); got != wantBody {
+		t.Fatalf("body want: %s, got: %s", wantBody, got)
+	}
+}
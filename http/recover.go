@@ -0,0 +1,47 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/MrEhbr/app"
+)
+
+// Recoverer recovers panics of handlers with app.Recover and writes them as problem details.
+type Recoverer struct {
+	// Writer writes recovered errors.
+	Writer ProblemWriter
+	// Options of app.Recover, e.g. app.Repanic.
+	Options []app.RecoverOption
+	// OnPanic is called with the recovered error before it's written, e.g. to log or report it.
+	OnPanic func(r *http.Request, err error)
+}
+
+// Handler returns the middleware recovering panics of the next handler.
+// http.ErrAbortHandler is always panicked again to abort the response as net/http expects.
+func (rc Recoverer) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		defer func() {
+			if err == nil {
+				return
+			}
+			if errors.Is(err, http.ErrAbortHandler) {
+				panic(http.ErrAbortHandler)
+			}
+
+			if rc.OnPanic != nil {
+				rc.OnPanic(r, err)
+			}
+			_ = rc.Writer.Write(w, r, err)
+		}()
+		defer app.Recover(&err, rc.Options...)
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Recover returns the middleware recovering panics of the next handler with the zero Recoverer.
+func Recover(next http.Handler) http.Handler {
+	return Recoverer{}.Handler(next)
+}
@@ -0,0 +1,62 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MrEhbr/app"
+)
+
+func TestRecoverer_Handler(t *testing.T) {
+	var recovered error
+	rc := Recoverer{OnPanic: func(_ *http.Request, err error) { recovered = err }}
+
+	h := rc.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("db is down")
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status want: %d, got: %d", http.StatusInternalServerError, rec.Code)
+	}
+
+	const wantBody = `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"An internal error has occurred","instance":"/users/42","code":"undefined_behavior"}`
+	if got := rec.Body.String(); got != wantBody {
+		t.Fatalf("body want:\n%s\ngot:\n%s", wantBody, got)
+	}
+
+	if code := app.ErrorCode(recovered); code != app.EBEHAVIOUR {
+		t.Fatalf("OnPanic ErrorCode() want: %s, got: %s", app.EBEHAVIOUR, code)
+	}
+}
+
+func TestRecover_noPanic(t *testing.T) {
+	h := Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("status want: %d, got: %d", http.StatusNoContent, rec.Code)
+	}
+}
+
+func TestRecover_abortHandler(t *testing.T) {
+	defer func() {
+		if v := recover(); v != http.ErrAbortHandler {
+			t.Fatalf("recover() want: %v, got: %v", http.ErrAbortHandler, v)
+		}
+	}()
+
+	h := Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	t.Fatal("panic is not propagated")
+}
@@ -0,0 +1,41 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/MrEhbr/app"
+	"github.com/MrEhbr/app/internal/mapx"
+)
+
+// Statuses overrides HTTP status codes of error codes from app.CodeInfo.
+type Statuses map[app.Code]int
+
+// With returns a copy of s extended with overrides.
+func (s Statuses) With(overrides Statuses) Statuses {
+	return mapx.Merge(s, overrides)
+}
+
+// Status returns the HTTP status code of the err.
+// If err is nil returns http.StatusOK,
+// if the code is neither in s nor registered with HTTPStatus returns http.StatusInternalServerError.
+func (s Statuses) Status(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	code := app.ErrorCode(err)
+	if status, ok := s[code]; ok {
+		return status
+	}
+
+	if info, ok := app.LookupCode(code); ok && info.HTTPStatus != 0 {
+		return info.HTTPStatus
+	}
+
+	return http.StatusInternalServerError
+}
+
+// Status returns the HTTP status code of the err using registered codes.
+func Status(err error) int {
+	return Statuses(nil).Status(err)
+}
@@ -0,0 +1,107 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/MrEhbr/app"
+)
+
+func TestStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{
+			err:  nil,
+			want: http.StatusOK,
+		},
+		{
+			err:  errors.New("test"),
+			want: http.StatusInternalServerError,
+		},
+		{
+			err:  &app.Error{Code: app.ENOTFOUND},
+			want: http.StatusNotFound,
+		},
+		{
+			err:  fmt.Errorf("%w", &app.Error{Code: app.EPERMISSIONDENIED}),
+			want: http.StatusForbidden,
+		},
+		{
+			err:  app.OpError("test", &app.Error{Code: app.ECONFLICT}),
+			want: http.StatusConflict,
+		},
+		{
+			err:  &app.Error{Code: app.EINVALID},
+			want: http.StatusBadRequest,
+		},
+	}
+
+	for i, tt := range tests {
+		if got := Status(tt.err); got != tt.want {
+			t.Fatalf("%d. Status want: %d, got: %d", i, tt.want, got)
+		}
+	}
+}
+
+func TestStatuses_With(t *testing.T) {
+	base := Statuses{app.ENOTFOUND: http.StatusGone}
+	statuses := base.With(Statuses{
+		app.ECONFLICT:  http.StatusPreconditionFailed,
+		"rate_limited": http.StatusTooManyRequests,
+	})
+
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{
+			err:  &app.Error{Code: app.ENOTFOUND},
+			want: http.StatusGone,
+		},
+		{
+			err:  &app.Error{Code: app.ECONFLICT},
+			want: http.StatusPreconditionFailed,
+		},
+		{
+			err:  &app.Error{Code: "rate_limited"},
+			want: http.StatusTooManyRequests,
+		},
+		{
+			err:  &app.Error{Code: app.EPERMISSIONDENIED},
+			want: http.StatusForbidden,
+		},
+	}
+
+	for i, tt := range tests {
+		if got := statuses.Status(tt.err); got != tt.want {
+			t.Fatalf("%d. Status want: %d, got: %d", i, tt.want, got)
+		}
+	}
+
+	if got := base.Status(&app.Error{Code: app.ECONFLICT}); got != http.StatusConflict {
+		t.Fatalf("base modified, want: %d, got: %d", http.StatusConflict, got)
+	}
+}
+
+var registerCodeOnce sync.Once
+
+func TestStatus_registered(t *testing.T) {
+	const code app.Code = "payment_required"
+	// the registry is global, so the code is registered once with -count=N
+	registerCodeOnce.Do(func() {
+		app.MustRegisterCode(code, app.CodeInfo{HTTPStatus: http.StatusPaymentRequired})
+	})
+
+	if got := Status(&app.Error{Code: code}); got != http.StatusPaymentRequired {
+		t.Fatalf("Status want: %d, got: %d", http.StatusPaymentRequired, got)
+	}
+
+	if got := Status(&app.Error{Code: "unregistered"}); got != http.StatusInternalServerError {
+		t.Fatalf("Status want: %d, got: %d", http.StatusInternalServerError, got)
+	}
+}
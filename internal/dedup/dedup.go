@@ -0,0 +1,138 @@
+// Package dedup implements the deduplication window shared by the logger adapters.
+package dedup
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultWindow is the default window of deduplication.
+	DefaultWindow = time.Minute
+	// DefaultFirst is the default number of entries written per fingerprint within the window.
+	DefaultFirst = 10
+)
+
+// Options configures the Window.
+type Options struct {
+	Window time.Duration
+	First  int
+	Now    func() time.Time
+}
+
+// NewOptions returns the default options with opts applied.
+func NewOptions[O ~func(*Options)](opts []O) Options {
+	o := Options{
+		Window: DefaultWindow,
+		First:  DefaultFirst,
+		Now:    time.Now,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// Summary is the last suppressed entry of the fingerprint and the number of suppressed entries.
+type Summary[T any] struct {
+	Entry      T
+	Suppressed int
+}
+
+// state is the state of the fingerprint within the window.
+type state[T any] struct {
+	start      time.Time
+	count      int
+	suppressed int
+	last       T
+}
+
+// Window counts entries per fingerprint, safe for concurrent use.
+type Window[T any] struct {
+	opts Options
+	// clone copies the suppressed entry, so it can be kept until the summary is written
+	clone     func(T) T
+	mu        sync.Mutex
+	states    map[string]*state[T]
+	lastSweep time.Time
+}
+
+// NewWindow returns the window, suppressed entries are kept as copies made by clone.
+func NewWindow[T any](o Options, clone func(T) T) *Window[T] {
+	return &Window[T]{
+		opts:      o,
+		clone:     clone,
+		states:    map[string]*state[T]{},
+		lastSweep: o.Now(),
+	}
+}
+
+// Observe counts the entry of the fingerprint, returns whether the entry should be written
+// and summaries of windows that are over.
+func (w *Window[T]) Observe(fingerprint string, entry T) (bool, []Summary[T]) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := w.opts.Now()
+	var summaries []Summary[T]
+	if now.Sub(w.lastSweep) >= w.opts.Window {
+		summaries = w.sweep(now)
+		w.lastSweep = now
+	}
+
+	s, ok := w.states[fingerprint]
+	if ok && now.Sub(s.start) >= w.opts.Window {
+		if s.suppressed > 0 {
+			summaries = append(summaries, Summary[T]{Entry: s.last, Suppressed: s.suppressed})
+		}
+		ok = false
+	}
+	if !ok {
+		s = &state[T]{start: now}
+		w.states[fingerprint] = s
+	}
+
+	s.count++
+	if s.count <= w.opts.First {
+		return true, summaries
+	}
+
+	s.suppressed++
+	s.last = w.clone(entry)
+
+	return false, summaries
+}
+
+// Flush returns summaries of suppressed entries and resets their suppressed count,
+// the windows go on.
+func (w *Window[T]) Flush() []Summary[T] {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var summaries []Summary[T]
+	for _, s := range w.states {
+		if s.suppressed > 0 {
+			summaries = append(summaries, Summary[T]{Entry: s.last, Suppressed: s.suppressed})
+			s.suppressed = 0
+		}
+	}
+
+	return summaries
+}
+
+// sweep removes states of windows that are over and returns summaries of those with suppressed entries.
+func (w *Window[T]) sweep(now time.Time) []Summary[T] {
+	var summaries []Summary[T]
+	for fingerprint, s := range w.states {
+		if now.Sub(s.start) < w.opts.Window {
+			continue
+		}
+		if s.suppressed > 0 {
+			summaries = append(summaries, Summary[T]{Entry: s.last, Suppressed: s.suppressed})
+		}
+		delete(w.states, fingerprint)
+	}
+
+	return summaries
+}
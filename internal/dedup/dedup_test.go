@@ -0,0 +1,65 @@
+package dedup
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWindow_Observe(t *testing.T) {
+	now := time.Unix(0, 0)
+	o := NewOptions([]func(*Options){func(o *Options) {
+		o.First = 1
+		o.Now = func() time.Time { return now }
+	}})
+	w := NewWindow(o, func(s string) string { return s })
+
+	for _, tt := range []struct {
+		fingerprint   string
+		entry         string
+		advance       time.Duration
+		wantWrite     bool
+		wantSummaries []Summary[string]
+	}{
+		{fingerprint: "a", entry: "a1", wantWrite: true},
+		{fingerprint: "a", entry: "a2"},
+		{fingerprint: "a", entry: "a3"},
+		{fingerprint: "b", entry: "b1", wantWrite: true},
+		// the window of a is over
+		{fingerprint: "b", entry: "b2", advance: DefaultWindow, wantWrite: true, wantSummaries: []Summary[string]{{Entry: "a3", Suppressed: 2}}},
+		{fingerprint: "a", entry: "a4", wantWrite: true},
+	} {
+		now = now.Add(tt.advance)
+		write, summaries := w.Observe(tt.fingerprint, tt.entry)
+		if write != tt.wantWrite {
+			t.Fatalf("Observe(%q) write want: %t, got: %t", tt.entry, tt.wantWrite, write)
+		}
+
+		if diff := cmp.Diff(tt.wantSummaries, summaries); diff != "" {
+			t.Fatalf("Observe(%q) summaries mismatch (-want +got):\n%s", tt.entry, diff)
+		}
+	}
+}
+
+func TestWindow_Flush(t *testing.T) {
+	o := NewOptions([]func(*Options){func(o *Options) { o.First = 1 }})
+	w := NewWindow(o, func(s string) string { return s })
+
+	w.Observe("a", "a1")
+	w.Observe("a", "a2")
+	w.Observe("a", "a3")
+
+	if diff := cmp.Diff([]Summary[string]{{Entry: "a3", Suppressed: 2}}, w.Flush()); diff != "" {
+		t.Fatalf("Flush() mismatch (-want +got):\n%s", diff)
+	}
+
+	if got := w.Flush(); got != nil {
+		t.Fatalf("second Flush() want nil, got: %v", got)
+	}
+
+	// the window goes on after Flush
+	if write, _ := w.Observe("a", "a4"); write {
+		t.Fatal("Observe after Flush want suppressed")
+	}
+}
@@ -0,0 +1,58 @@
+// Package errjson renders errors as JSON for log adapters without structured encoders.
+package errjson
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/MrEhbr/app"
+)
+
+type jsonErr struct {
+	Msg    string                 `json:"msg"`
+	Detail string                 `json:"detail,omitempty"`
+	Code   string                 `json:"code"`
+	Trace  []string               `json:"trace"`
+	Fields map[string]interface{} `json:"fields,omitempty"`
+	Errors []json.RawMessage      `json:"errors,omitempty"`
+	Stack  []string               `json:"stack,omitempty"`
+}
+
+// Marshal returns the JSON encoding of the err.
+// Errors other than app.Error are rendered with the message only.
+func Marshal(err error) ([]byte, error) {
+	if !errors.Is(err, &app.Error{}) {
+		return json.Marshal(struct {
+			Msg string `json:"msg"`
+		}{Msg: err.Error()})
+	}
+
+	v := jsonErr{
+		Msg:    app.ErrorMessageDefault(err, ""),
+		Detail: app.ErrorDetail(err),
+		Code:   app.ErrorCode(err).String(),
+		Trace:  app.ErrorTrace(err),
+		Fields: app.RedactedErrorFields(err),
+	}
+	if v.Trace == nil {
+		v.Trace = []string{}
+	}
+	var errs app.Errors
+	if errors.As(err, &errs) {
+		for _, origin := range errs {
+			if origin == nil {
+				continue
+			}
+			data, merr := Marshal(origin)
+			if merr != nil {
+				return nil, merr
+			}
+			v.Errors = append(v.Errors, data)
+		}
+	}
+	if stack := app.ErrorStack(err); len(stack) > 0 {
+		v.Stack = stack.Strings()
+	}
+
+	return json.Marshal(v)
+}
@@ -0,0 +1,45 @@
+package errjson
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/MrEhbr/app"
+)
+
+func TestMarshal(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "std error",
+			err:  errors.New("foo"),
+			want: `{"msg":"foo"}`,
+		},
+		{
+			name: "app error",
+			err:  &app.Error{Op: "test", Code: app.ETEST, Message: "foo", Detail: "bar", Fields: map[string]interface{}{"password": "123"}},
+			want: `{"msg":"foo","detail":"bar","code":"test_error_code","trace":["test"],"fields":{"password":"[REDACTED]"}}`,
+		},
+		{
+			name: "multiple errors",
+			err:  app.Join(&app.Error{Op: "foo", Code: app.EINVALID, Message: "invalid"}, nil),
+			want: `{"msg":"invalid","code":"invalid","trace":["foo"],"errors":[{"msg":"invalid","code":"invalid","trace":["foo"]}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Marshal(tt.err)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(got) != tt.want {
+				t.Fatalf("want: %s, got: %s", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+// Package lru implements the set of keys bounded by evicting the least recently used ones.
+package lru
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Set is the set of at most size keys, safe for concurrent use.
+type Set[K comparable] struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List
+	items map[K]*list.Element
+}
+
+// NewSet returns the set of at most size keys, not positive size means no limit.
+func NewSet[K comparable](size int) *Set[K] {
+	return &Set[K]{size: size, order: list.New(), items: map[K]*list.Element{}}
+}
+
+// Add adds the key and marks it as the most recently used one,
+// returns false if the key is already in the set.
+// The least recently used key is evicted if the set is full.
+func (s *Set[K]) Add(key K) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.items[key]; ok {
+		s.order.MoveToFront(el)
+		return false
+	}
+
+	s.items[key] = s.order.PushFront(key)
+	if s.size > 0 && s.order.Len() > s.size {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.items, oldest.Value.(K))
+	}
+
+	return true
+}
+
+// Len returns the number of keys in the set.
+func (s *Set[K]) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.order.Len()
+}
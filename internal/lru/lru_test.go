@@ -0,0 +1,39 @@
+package lru
+
+import "testing"
+
+func TestSet_Add(t *testing.T) {
+	s := NewSet[string](2)
+
+	for _, tt := range []struct {
+		key  string
+		want bool
+	}{
+		{key: "a", want: true},
+		{key: "b", want: true},
+		{key: "a", want: false},
+		// evicts b as the least recently used
+		{key: "c", want: true},
+		{key: "a", want: false},
+		{key: "b", want: true},
+	} {
+		if got := s.Add(tt.key); got != tt.want {
+			t.Fatalf("Add(%q) want: %t, got: %t", tt.key, tt.want, got)
+		}
+	}
+
+	if got := s.Len(); got != 2 {
+		t.Fatalf("Len want: 2, got: %d", got)
+	}
+}
+
+func TestSet_unbounded(t *testing.T) {
+	s := NewSet[int](0)
+	for i := 0; i < 100; i++ {
+		s.Add(i)
+	}
+
+	if got := s.Len(); got != 100 {
+		t.Fatalf("Len want: 100, got: %d", got)
+	}
+}
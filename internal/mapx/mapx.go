@@ -0,0 +1,16 @@
+// Package mapx holds helpers of maps shared by the packages of the module.
+package mapx
+
+// Merge returns a new map with entries of m overridden by entries of overrides.
+// Neither m nor overrides is modified.
+func Merge[M ~map[K]V, K comparable, V any](m, overrides M) M {
+	res := make(M, len(m)+len(overrides))
+	for k, v := range m {
+		res[k] = v
+	}
+	for k, v := range overrides {
+		res[k] = v
+	}
+
+	return res
+}
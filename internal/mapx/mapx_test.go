@@ -0,0 +1,22 @@
+package mapx
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMerge(t *testing.T) {
+	type codes map[string]int
+
+	base := codes{"a": 1, "b": 2}
+	got := Merge(base, codes{"b": 3, "c": 4})
+
+	if diff := cmp.Diff(codes{"a": 1, "b": 3, "c": 4}, got); diff != "" {
+		t.Fatalf("Merge() mismatch (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(codes{"a": 1, "b": 2}, base); diff != "" {
+		t.Fatalf("base modified (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"github.com/MrEhbr/app/internal/lru"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	// ExemplarLabel is the label of exemplars with the trace id.
+	ExemplarLabel = "trace_id"
+
+	// DefaultFingerprintsLimit is the default number of fingerprints remembered by FingerprintCounter.
+	DefaultFingerprintsLimit = 10000
+)
+
+// AddWithExemplar increments the counter with the exemplar of the trace id,
+// or without it if the trace id is empty or the counter doesn't support exemplars.
+func AddWithExemplar(counter prometheus.Counter, traceID string) {
+	if adder, ok := counter.(prometheus.ExemplarAdder); ok && traceID != "" {
+		adder.AddWithExemplar(1, prometheus.Labels{ExemplarLabel: traceID})
+		return
+	}
+
+	counter.Inc()
+}
+
+// FingerprintCounter counts first-seen error fingerprints.
+type FingerprintCounter struct {
+	counter *prometheus.CounterVec
+	seen    *lru.Set[[2]string]
+}
+
+// NewFingerprintCounter returns the counter remembering at most limit fingerprints, nil if the metric name is empty.
+func NewFingerprintCounter(registry prometheus.Registerer, metricName string, limit int) *FingerprintCounter {
+	if metricName == "" {
+		return nil
+	}
+
+	return &FingerprintCounter{
+		seen: lru.NewSet[[2]string](limit),
+		counter: promauto.With(registry).NewCounterVec(prometheus.CounterOpts{
+			Name: metricName,
+			Help: "Number of first-seen error fingerprints grouped by code",
+		}, []string{"code"}),
+	}
+}
+
+// Observe increments the counter if the fingerprint of the code and the op is seen first time.
+func (c *FingerprintCounter) Observe(code, op string) {
+	if c == nil {
+		return
+	}
+
+	if c.seen.Add([2]string{code, op}) {
+		c.counter.WithLabelValues(code).Inc()
+	}
+}
@@ -0,0 +1,24 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestFingerprintCounter_Observe(t *testing.T) {
+	c := NewFingerprintCounter(prometheus.NewRegistry(), "error_fingerprints", 1)
+	c.Observe("not_found", "users.Get")
+	c.Observe("not_found", "users.Get")
+	// evicts users.Get
+	c.Observe("not_found", "orders.Get")
+	c.Observe("not_found", "users.Get")
+
+	if got := testutil.ToFloat64(c.counter.WithLabelValues("not_found")); got != 3 {
+		t.Fatalf("counter want: 3, got: %v", got)
+	}
+
+	// nil counter is disabled
+	NewFingerprintCounter(prometheus.NewRegistry(), "", 1).Observe("not_found", "users.Get")
+}
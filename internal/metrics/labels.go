@@ -0,0 +1,76 @@
+// Package metrics holds the parts of the error metrics shared by the logger adapters.
+package metrics
+
+import "sync"
+
+const (
+	// NoneLabelValue is the value of a label missing in the entry.
+	NoneLabelValue = "none"
+	// OtherLabelValue is the value labels are collapsed into above the limit.
+	OtherLabelValue = "other"
+
+	// DefaultMaxLabelValues is the default number of distinct values of a metric label.
+	DefaultMaxLabelValues = 100
+)
+
+// LabelLimit bounds the cardinality of a label, safe for concurrent use.
+type LabelLimit struct {
+	mu      sync.Mutex
+	max     int
+	allowed map[string]struct{}
+	seen    map[string]struct{}
+}
+
+// NewLabelLimit returns the limit of max distinct values, not positive max means no limit.
+// If allowed is not empty, only the allowed values are kept.
+func NewLabelLimit(max int, allowed []string) *LabelLimit {
+	l := &LabelLimit{max: max, seen: map[string]struct{}{}}
+	if len(allowed) > 0 {
+		l.allowed = make(map[string]struct{}, len(allowed))
+		for _, v := range allowed {
+			l.allowed[v] = struct{}{}
+		}
+	}
+
+	return l
+}
+
+// Value returns the v, or OtherLabelValue if the v is not allowed or the limit of values is reached.
+// NoneLabelValue is always kept.
+func (l *LabelLimit) Value(v string) string {
+	if v == NoneLabelValue {
+		return v
+	}
+
+	if l.allowed != nil {
+		if _, ok := l.allowed[v]; !ok {
+			return OtherLabelValue
+		}
+	}
+
+	if l.max <= 0 {
+		return v
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.seen[v]; ok {
+		return v
+	}
+	if len(l.seen) >= l.max {
+		return OtherLabelValue
+	}
+	l.seen[v] = struct{}{}
+
+	return v
+}
+
+// OrNone returns the v, or NoneLabelValue if the v is empty.
+func OrNone(v string) string {
+	if v == "" {
+		return NoneLabelValue
+	}
+
+	return v
+}
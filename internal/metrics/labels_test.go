@@ -0,0 +1,41 @@
+package metrics
+
+import "testing"
+
+func TestLabelLimit_Value(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit *LabelLimit
+		in    []string
+		want  []string
+	}{
+		{
+			name:  "max values",
+			limit: NewLabelLimit(2, nil),
+			in:    []string{"a", "b", "c", "a", NoneLabelValue},
+			want:  []string{"a", "b", OtherLabelValue, "a", NoneLabelValue},
+		},
+		{
+			name:  "allowed values",
+			limit: NewLabelLimit(0, []string{"a"}),
+			in:    []string{"a", "b", NoneLabelValue},
+			want:  []string{"a", OtherLabelValue, NoneLabelValue},
+		},
+		{
+			name:  "no limit",
+			limit: NewLabelLimit(0, nil),
+			in:    []string{"a", "b", "c"},
+			want:  []string{"a", "b", "c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i, v := range tt.in {
+				if got := tt.limit.Value(v); got != tt.want[i] {
+					t.Fatalf("Value(%q) want: %s, got: %s", v, tt.want[i], got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// jsonError is the JSON representation of Error.
+type jsonError struct {
+	Op         string                 `json:"op,omitempty"`
+	Code       Code                   `json:"code,omitempty"`
+	Message    string                 `json:"message,omitempty"`
+	Detail     string                 `json:"detail,omitempty"`
+	MessageKey string                 `json:"message_key,omitempty"`
+	GroupKey   string                 `json:"group_key,omitempty"`
+	Fields     map[string]interface{} `json:"fields,omitempty"`
+	Retryable  *bool                  `json:"retryable,omitempty"`
+	Temporary  *bool                  `json:"temporary,omitempty"`
+	RetryAfter string                 `json:"retry_after,omitempty"`
+	Err        json.RawMessage        `json:"err,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+// The whole chain is serialized: *Error as an object, Errors as an array
+// and other errors as their message, unless they wrap an *Error or Errors.
+// The stack is not serialized.
+func (e *Error) MarshalJSON() ([]byte, error) {
+	v := jsonError{
+		Op:         e.Op,
+		Code:       e.Code,
+		Message:    e.Message,
+		Detail:     e.Detail,
+		MessageKey: e.MessageKey,
+		GroupKey:   e.GroupKey,
+		Fields:     e.Fields,
+		Retryable:  e.Retryable,
+		Temporary:  e.Temporary,
+	}
+	if e.RetryAfter > 0 {
+		v.RetryAfter = e.RetryAfter.String()
+	}
+
+	if e.Err != nil {
+		raw, err := marshalErr(e.Err)
+		if err != nil {
+			return nil, err
+		}
+		v.Err = raw
+	}
+
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// Errors reduced to their message are restored with errors.New.
+// Numbers of Fields are restored as json.Number to keep their precision.
+func (e *Error) UnmarshalJSON(data []byte) error {
+	var v jsonError
+	if err := decodeJSON(data, &v); err != nil {
+		return err
+	}
+
+	*e = Error{
+		Op:         v.Op,
+		Code:       v.Code,
+		Message:    v.Message,
+		Detail:     v.Detail,
+		MessageKey: v.MessageKey,
+		GroupKey:   v.GroupKey,
+		Fields:     v.Fields,
+		Retryable:  v.Retryable,
+		Temporary:  v.Temporary,
+	}
+
+	if v.RetryAfter != "" {
+		d, err := time.ParseDuration(v.RetryAfter)
+		if err != nil {
+			return err
+		}
+		e.RetryAfter = d
+	}
+
+	if raw, ok := e.Fields[ViolationsKey]; ok {
+		violations, err := unmarshalViolations(raw)
+		if err != nil {
+			return err
+		}
+		e.Fields[ViolationsKey] = violations
+	}
+
+	err, uerr := unmarshalErr(v.Err)
+	if uerr != nil {
+		return uerr
+	}
+	e.Err = err
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, errors are serialized the same way as Error.Err.
+func (es Errors) MarshalJSON() ([]byte, error) {
+	items := make([]json.RawMessage, 0, len(es))
+	for _, err := range es {
+		if err == nil {
+			continue
+		}
+
+		raw, merr := marshalErr(err)
+		if merr != nil {
+			return nil, merr
+		}
+		items = append(items, raw)
+	}
+
+	return json.Marshal(items)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (es *Errors) UnmarshalJSON(data []byte) error {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+
+	res := make(Errors, 0, len(items))
+	for _, item := range items {
+		err, uerr := unmarshalErr(item)
+		if uerr != nil {
+			return uerr
+		}
+		if err != nil {
+			res = append(res, err)
+		}
+	}
+	*es = res
+
+	return nil
+}
+
+func marshalErr(err error) (json.RawMessage, error) {
+	switch e := err.(type) {
+	case *Error:
+		return e.MarshalJSON()
+	case Errors:
+		return e.MarshalJSON()
+	}
+
+	// skip foreign wrappers to keep the app errors
+	target, errs := find(err)
+	switch {
+	case errs != nil:
+		return errs.MarshalJSON()
+	case target != nil:
+		return target.MarshalJSON()
+	}
+
+	return json.Marshal(err.Error())
+}
+
+func unmarshalErr(raw json.RawMessage) (error, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil, nil
+	}
+
+	switch raw[0] {
+	case '{':
+		e := &Error{}
+		if err := e.UnmarshalJSON(raw); err != nil {
+			return nil, err
+		}
+		return e, nil
+	case '[':
+		var errs Errors
+		if err := errs.UnmarshalJSON(raw); err != nil {
+			return nil, err
+		}
+		return errs, nil
+	default:
+		var msg string
+		if err := json.Unmarshal(raw, &msg); err != nil {
+			return nil, err
+		}
+		return errors.New(msg), nil
+	}
+}
+
+// decodeJSON is json.Unmarshal decoding numbers as json.Number.
+// The data is a single value, as passed to json.Unmarshaler.
+func decodeJSON(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	return dec.Decode(v)
+}
+
+func unmarshalViolations(v interface{}) ([]Violation, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []Violation
+	if err := decodeJSON(data, &violations); err != nil {
+		return nil, err
+	}
+
+	return violations, nil
+}
+
+var (
+	_ json.Marshaler   = &Error{}
+	_ json.Unmarshaler = &Error{}
+	_ json.Marshaler   = Errors{}
+	_ json.Unmarshaler = &Errors{}
+)
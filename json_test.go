@@ -0,0 +1,151 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestError_JSON(t *testing.T) {
+	retryable := false
+	tests := []struct {
+		name     string
+		err      *Error
+		wantJSON string
+	}{
+		{
+			name:     "single",
+			err:      &Error{Op: "test", Code: ENOTFOUND, Message: "user not found", Detail: "no rows", MessageKey: "user", GroupKey: "users", Fields: map[string]interface{}{"id": "42"}},
+			wantJSON: `{"op":"test","code":"not_found","message":"user not found","detail":"no rows","message_key":"user","group_key":"users","fields":{"id":"42"}}`,
+		},
+		{
+			name: "chain",
+			err: &Error{
+				Op:         "outer",
+				Retryable:  &retryable,
+				RetryAfter: time.Second,
+				Err: fmt.Errorf("wrapped: %w", &Error{
+					Op:     "inner",
+					Code:   ECONFLICT,
+					Fields: map[string]interface{}{"version": "2"},
+					Err:    errors.New("foreign"),
+				}),
+			},
+			wantJSON: `{"op":"outer","retryable":false,"retry_after":"1s","err":{"op":"inner","code":"conflict","fields":{"version":"2"},"err":"foreign"}}`,
+		},
+		{
+			name: "multiple errors",
+			err: &Error{
+				Op: "batch",
+				Err: Errors{
+					&Error{Op: "foo", Code: EINVALID},
+					errors.New("bar"),
+				},
+			},
+			wantJSON: `{"op":"batch","err":[{"op":"foo","code":"invalid"},"bar"]}`,
+		},
+		{
+			name: "violations",
+			err: &Error{
+				Op:   "validate",
+				Code: EINVALID,
+				Fields: map[string]interface{}{
+					ViolationsKey: []Violation{{Field: "name", Rule: "required", Value: "", Message: "name is required"}},
+				},
+			},
+			wantJSON: `{"op":"validate","code":"invalid","fields":{"violations":[{"field":"name","rule":"required","value":"","message":"name is required"}]}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.err)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(data) != tt.wantJSON {
+				t.Fatalf("json want:\n%s\ngot:\n%s", tt.wantJSON, data)
+			}
+
+			got := &Error{}
+			if err := json.Unmarshal(data, got); err != nil {
+				t.Fatal(err)
+			}
+
+			if ErrorCode(got) != ErrorCode(tt.err) {
+				t.Fatalf("ErrorCode want: %s, got: %s", ErrorCode(tt.err), ErrorCode(got))
+			}
+
+			if ErrorMessage(got) != ErrorMessage(tt.err) {
+				t.Fatalf("ErrorMessage want: %s, got: %s", ErrorMessage(tt.err), ErrorMessage(got))
+			}
+
+			if IsRetryable(got) != IsRetryable(tt.err) {
+				t.Fatalf("IsRetryable want: %t, got: %t", IsRetryable(tt.err), IsRetryable(got))
+			}
+
+			if RetryAfter(got) != RetryAfter(tt.err) {
+				t.Fatalf("RetryAfter want: %s, got: %s", RetryAfter(tt.err), RetryAfter(got))
+			}
+
+			if diff := cmp.Diff(ErrorTraces(tt.err), ErrorTraces(got)); diff != "" {
+				t.Fatalf("ErrorTraces() mismatch (-want +got):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(ErrorFields(tt.err), ErrorFields(got)); diff != "" {
+				t.Fatalf("ErrorFields() mismatch (-want +got):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(ErrorViolations(tt.err), ErrorViolations(got)); diff != "" {
+				t.Fatalf("ErrorViolations() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestError_JSON_number(t *testing.T) {
+	data := []byte(`{"code":"invalid","fields":{"id":9007199254740993,"ratio":0.5,"violations":[{"field":"age","rule":"min","value":17,"message":"age is too small"}]}}`)
+
+	got := &Error{}
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatal(err)
+	}
+
+	wantFields := map[string]interface{}{
+		"id":          json.Number("9007199254740993"),
+		"ratio":       json.Number("0.5"),
+		ViolationsKey: []Violation{{Field: "age", Rule: "min", Value: json.Number("17"), Message: "age is too small"}},
+	}
+	if diff := cmp.Diff(wantFields, got.Fields); diff != "" {
+		t.Fatalf("Fields mismatch (-want +got):\n%s", diff)
+	}
+
+	res, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(res) != string(data) {
+		t.Fatalf("json want:\n%s\ngot:\n%s", data, res)
+	}
+}
+
+func TestError_UnmarshalJSON_invalid(t *testing.T) {
+	tests := []string{
+		`{"op":1}`,
+		`{"retry_after":"foo"}`,
+		`{"err":1}`,
+		`{"err":[1]}`,
+	}
+
+	for i, tt := range tests {
+		if err := json.Unmarshal([]byte(tt), &Error{}); err == nil {
+			t.Fatalf("%d. want error, got nil", i)
+		}
+	}
+}
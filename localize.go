@@ -0,0 +1,241 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultLanguage is the language used by LocalizedMessage when there is no message in the requested language.
+const DefaultLanguage = "en"
+
+// Catalog holds localized message templates of one language.
+// Messages are keyed by "code" or "code.message_key", see MessageID.
+// Templates use text/template syntax and are executed with redacted Error.Fields, e.g.
+//
+//	not_found.user: "User {{.id}} not found"
+type Catalog struct {
+	lang      string
+	templates map[string]*template.Template
+}
+
+// NewCatalog parses the messages of the language.
+// Returns EINVALID error if the language is empty or a message is not a valid template.
+func NewCatalog(lang string, messages map[string]string) (*Catalog, error) {
+	lang = normalizeLanguage(lang)
+	if lang == "" {
+		return nil, &Error{Op: CurrentFunctionName(), Code: EINVALID, Message: "language is empty"}
+	}
+
+	c := &Catalog{lang: lang, templates: make(map[string]*template.Template, len(messages))}
+	for id, msg := range messages {
+		tmpl, err := template.New(id).Option("missingkey=error").Parse(msg)
+		if err != nil {
+			return nil, &Error{
+				Op:     CurrentFunctionName(),
+				Code:   EINVALID,
+				Fields: map[string]interface{}{"lang": lang, "id": id},
+				Err:    err,
+			}
+		}
+		c.templates[id] = tmpl
+	}
+
+	return c, nil
+}
+
+// Language returns the language of the catalog.
+func (c *Catalog) Language() string {
+	return c.lang
+}
+
+// Message executes the template with the id using the fields.
+// Returns false if there is no such message or the template failed, e.g. because of missing field.
+func (c *Catalog) Message(id string, fields map[string]interface{}) (string, bool) {
+	tmpl, ok := c.templates[id]
+	if !ok {
+		return "", false
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, fields); err != nil {
+		return "", false
+	}
+
+	return buf.String(), true
+}
+
+// MessageID returns the id of the message in a catalog: the code, or "code.key" if the key is not empty.
+func MessageID(code Code, key string) string {
+	if key == "" {
+		return code.String()
+	}
+
+	return code.String() + "." + key
+}
+
+var catalogs = struct {
+	sync.RWMutex
+	defaultLang string
+	langs       map[string]*Catalog
+}{
+	defaultLang: DefaultLanguage,
+	langs:       map[string]*Catalog{},
+}
+
+// RegisterCatalog registers the catalog, messages of a catalog already registered for the language are merged.
+func RegisterCatalog(c *Catalog) {
+	catalogs.Lock()
+	defer catalogs.Unlock()
+
+	existing, ok := catalogs.langs[c.lang]
+	if !ok {
+		existing = &Catalog{lang: c.lang, templates: make(map[string]*template.Template, len(c.templates))}
+		catalogs.langs[c.lang] = existing
+	}
+	for id, tmpl := range c.templates {
+		existing.templates[id] = tmpl
+	}
+}
+
+// SetDefaultLanguage sets the language used when there is no message in the requested language,
+// DefaultLanguage by default. The catalog of the language may be registered later.
+func SetDefaultLanguage(lang string) {
+	catalogs.Lock()
+	defer catalogs.Unlock()
+
+	catalogs.defaultLang = normalizeLanguage(lang)
+}
+
+// LoadCatalogs registers catalogs from files of the fsys matching the pattern, e.g. files embedded with embed.FS.
+// The language is the file name without extension, e.g. "en.json" or "pt-BR.yaml".
+// A file contains a flat object of message ids to templates in JSON or YAML format,
+// depending on the extension: .json, .yaml or .yml.
+func LoadCatalogs(fsys fs.FS, pattern string) error {
+	files, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return OpError(CurrentFunctionName(), err)
+	}
+
+	for _, file := range files {
+		c, err := loadCatalog(fsys, file)
+		if err != nil {
+			return OpError(CurrentFunctionName(), err)
+		}
+		RegisterCatalog(c)
+	}
+
+	return nil
+}
+
+func loadCatalog(fsys fs.FS, file string) (*Catalog, error) {
+	data, err := fs.ReadFile(fsys, file)
+	if err != nil {
+		return nil, err
+	}
+
+	ext := path.Ext(file)
+	messages := map[string]string{}
+	switch ext {
+	case ".json":
+		err = json.Unmarshal(data, &messages)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &messages)
+	default:
+		return nil, &Error{
+			Op:      CurrentFunctionName(),
+			Code:    EUNSUPPORTED,
+			Message: "unsupported catalog format",
+			Fields:  map[string]interface{}{"file": file},
+		}
+	}
+	if err != nil {
+		return nil, &Error{Op: CurrentFunctionName(), Code: ECANNOTDECODE, Fields: map[string]interface{}{"file": file}, Err: err}
+	}
+
+	return NewCatalog(strings.TrimSuffix(path.Base(file), ext), messages)
+}
+
+// LocalizedMessage returns the message of the err for users in the lang.
+// The message is looked up by the code and Error.MessageKey, then by the code only,
+// in the lang, its base language ("pt" for "pt-BR") and the default language, in that order.
+// Returns DefaultErrorMessage if there is no such message,
+// Error.Message is not used, because it isn't localized.
+func LocalizedMessage(err error, lang string) string {
+	if err == nil {
+		return ""
+	}
+
+	code := ErrorCode(err)
+	ids := []string{code.String()}
+	if key := messageKey(err); key != "" {
+		ids = []string{MessageID(code, key), code.String()}
+	}
+
+	fields := RedactedErrorFields(err)
+
+	catalogs.RLock()
+	defer catalogs.RUnlock()
+
+	for _, l := range languages(lang, catalogs.defaultLang) {
+		c, ok := catalogs.langs[l]
+		if !ok {
+			continue
+		}
+
+		for _, id := range ids {
+			if msg, ok := c.Message(id, fields); ok {
+				return msg
+			}
+		}
+	}
+
+	return DefaultErrorMessage
+}
+
+// messageKey returns the first non-empty Error.MessageKey of the chain.
+func messageKey(err error) string {
+	for _, e := range chain(err) {
+		if e.MessageKey != "" {
+			return e.MessageKey
+		}
+	}
+
+	return ""
+}
+
+// languages returns the fallback chain of the lang.
+func languages(lang, defaultLang string) []string {
+	var langs []string
+	add := func(l string) {
+		if l == "" {
+			return
+		}
+		for _, v := range langs {
+			if v == l {
+				return
+			}
+		}
+		langs = append(langs, l)
+	}
+
+	lang = normalizeLanguage(lang)
+	add(lang)
+	if i := strings.IndexByte(lang, '-'); i > 0 {
+		add(lang[:i])
+	}
+	add(defaultLang)
+
+	return langs
+}
+
+// normalizeLanguage converts the language tag to lower case with hyphen as separator, e.g. "pt_BR" to "pt-br".
+func normalizeLanguage(lang string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(lang), "_", "-"))
+}
@@ -0,0 +1,177 @@
+package app
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+//go:embed testdata/locales
+var testLocales embed.FS
+
+func TestLocalizedMessage(t *testing.T) {
+	if err := LoadCatalogs(testLocales, "testdata/locales/*"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		lang string
+		want string
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			lang: "en",
+			want: "",
+		},
+		{
+			name: "code",
+			err:  &Error{Code: ENOTFOUND},
+			lang: "en",
+			want: "Not found",
+		},
+		{
+			name: "message key with fields",
+			err:  &Error{Code: ENOTFOUND, MessageKey: "user", Fields: map[string]interface{}{"id": 42}},
+			lang: "ru",
+			want: "Пользователь 42 не найден",
+		},
+		{
+			name: "message key of wrapped error",
+			err:  OpError("outer", fmt.Errorf("wrapped: %w", &Error{Code: ENOTFOUND, MessageKey: "user", Fields: map[string]interface{}{"id": 42}})),
+			lang: "en",
+			want: "User 42 not found",
+		},
+		{
+			name: "unknown message key",
+			err:  &Error{Code: ENOTFOUND, MessageKey: "order"},
+			lang: "ru",
+			want: "Не найдено",
+		},
+		{
+			name: "missing field",
+			err:  &Error{Code: ENOTFOUND, MessageKey: "user"},
+			lang: "ru",
+			want: "Не найдено",
+		},
+		{
+			name: "sensitive field",
+			err:  &Error{Code: ECONFLICT, MessageKey: "email", Fields: map[string]interface{}{"email": "john@example.com"}},
+			lang: "ru",
+			want: "Адрес [REDACTED] уже занят",
+		},
+		{
+			name: "base language",
+			err:  &Error{Code: ENOTFOUND},
+			lang: "pt_BR",
+			want: "Não encontrado",
+		},
+		{
+			name: "default language",
+			err:  &Error{Code: EINVALID},
+			lang: "ru",
+			want: "Invalid request",
+		},
+		{
+			name: "unknown language",
+			err:  &Error{Code: ENOTFOUND},
+			lang: "de",
+			want: "Not found",
+		},
+		{
+			name: "internal",
+			err:  errors.New("db is down"),
+			lang: "ru",
+			want: "Something went wrong",
+		},
+		{
+			name: "message is not localized",
+			err:  &Error{Code: EALREADYEXISTS, Message: "user already exists"},
+			lang: "ru",
+			want: DefaultErrorMessage,
+		},
+		{
+			name: "default message",
+			err:  &Error{Code: EPERMISSIONDENIED},
+			lang: "ru",
+			want: DefaultErrorMessage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LocalizedMessage(tt.err, tt.lang); got != tt.want {
+				t.Fatalf("LocalizedMessage() want: %q, got: %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLoadCatalogs_invalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		file      string
+		data      string
+		wantCode  Code
+		wantTrace []string
+	}{
+		{
+			name:      "unsupported format",
+			file:      "en.toml",
+			data:      `not_found = "Not found"`,
+			wantCode:  EUNSUPPORTED,
+			wantTrace: []string{"github.com/MrEhbr/app.LoadCatalogs", "github.com/MrEhbr/app.loadCatalog"},
+		},
+		{
+			name:      "invalid json",
+			file:      "en.json",
+			data:      `{"not_found":1}`,
+			wantCode:  ECANNOTDECODE,
+			wantTrace: []string{"github.com/MrEhbr/app.LoadCatalogs", "github.com/MrEhbr/app.loadCatalog"},
+		},
+		{
+			name:      "invalid template",
+			file:      "en.yaml",
+			data:      `not_found: "{{.id"`,
+			wantCode:  EINVALID,
+			wantTrace: []string{"github.com/MrEhbr/app.LoadCatalogs", "github.com/MrEhbr/app.NewCatalog"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{tt.file: {Data: []byte(tt.data)}}
+			err := LoadCatalogs(fsys, "*")
+			if err == nil {
+				t.Fatal("want error, got nil")
+			}
+
+			if code := ErrorCode(err); code != tt.wantCode {
+				t.Fatalf("ErrorCode() want: %s, got: %s", tt.wantCode, code)
+			}
+
+			if diff := cmp.Diff(tt.wantTrace, ErrorTrace(err)); diff != "" {
+				t.Fatalf("ErrorTrace() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSetDefaultLanguage(t *testing.T) {
+	if err := LoadCatalogs(testLocales, "testdata/locales/*"); err != nil {
+		t.Fatal(err)
+	}
+
+	SetDefaultLanguage("RU")
+	t.Cleanup(func() { SetDefaultLanguage(DefaultLanguage) })
+
+	if got, want := LocalizedMessage(&Error{Code: ENOTFOUND}, "de"), "Не найдено"; got != want {
+		t.Fatalf("LocalizedMessage() want: %q, got: %q", want, got)
+	}
+}
@@ -0,0 +1,67 @@
+package gokit
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/MrEhbr/app"
+	"github.com/MrEhbr/app/internal/errjson"
+)
+
+// err is the error rendered as the structure,
+// it doesn't implement error to not be rendered as a string by log.NewJSONLogger.
+type err struct {
+	origin error
+}
+
+func (e err) Unwrap() error {
+	return e.origin
+}
+
+// String implements fmt.Stringer, so log.NewLogfmtLogger renders the error message.
+func (e err) String() string {
+	return e.origin.Error()
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e err) MarshalJSON() ([]byte, error) {
+	return errjson.Marshal(e.origin)
+}
+
+// Error returns the value of the error to be logged with log.NewJSONLogger, nil if the error is nil.
+func Error(e error) interface{} {
+	if e == nil {
+		return nil
+	}
+	return err{origin: e}
+}
+
+// FingerprintKey is the key of the fingerprint.
+const FingerprintKey = "fingerprint"
+
+// Fingerprint returns app.Fingerprint of the error to be logged with FingerprintKey, nil if the error is nil, e.g.
+//
+//	logger.Log("err", gokit.Error(err), gokit.FingerprintKey, gokit.Fingerprint(err))
+func Fingerprint(e error, opts ...app.FingerprintOption) interface{} {
+	if e == nil {
+		return nil
+	}
+	return app.Fingerprint(e, opts...)
+}
+
+// originError returns the error of the value, logged either as is or with Error.
+func originError(v interface{}) (error, bool) {
+	switch e := v.(type) {
+	case err:
+		return e.origin, true
+	case error:
+		return e, true
+	}
+
+	return nil, false
+}
+
+var (
+	_ json.Marshaler = err{}
+	_ fmt.Stringer   = err{}
+)
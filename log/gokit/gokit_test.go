@@ -0,0 +1,75 @@
+package gokit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/MrEhbr/app"
+	"github.com/go-kit/log"
+)
+
+func ExampleNew() {
+	logger := log.NewJSONLogger(os.Stdout)
+
+	_ = logger.Log("msg", "nil error", "error", Error(nil))
+	_ = logger.Log("msg", "std error", "error", Error(errors.New("foo")))
+	_ = logger.Log("msg", "wrapped std error", "error", Error(app.ErrorWithCode(errors.New("foo"), app.ETEST)))
+	_ = logger.Log("msg", "error with fields", "error", Error(&app.Error{Op: "test", Code: app.ETEST, Message: "foo", Fields: map[string]interface{}{"foo": "bar"}}))
+	_ = logger.Log("msg", "multiple errors", "error", Error(app.OpError("batch", app.Join(
+		&app.Error{Op: "foo", Code: app.EINVALID, Message: "invalid", Fields: map[string]interface{}{"foo": "bar"}},
+		&app.Error{Op: "bar", Code: app.ENOTFOUND, Message: "not found"},
+	))))
+	_ = logger.Log("msg", "validation error", "error", Error(app.NewValidation().Add("name", "required", "", "name is required").Err()))
+	fpErr := &app.Error{Op: "test", Code: app.ETEST, Message: "foo"}
+	_ = logger.Log("msg", "error with fingerprint", "error", Error(fpErr), FingerprintKey, Fingerprint(fpErr))
+	// Output: {"error":null,"msg":"nil error"}
+	// {"error":{"msg":"foo"},"msg":"std error"}
+	// {"error":{"msg":"foo","code":"test_error_code","trace":["github.com/MrEhbr/app/log/gokit.ExampleNew"]},"msg":"wrapped std error"}
+	// {"error":{"msg":"foo","code":"test_error_code","trace":["test"],"fields":{"foo":"bar"}},"msg":"error with fields"}
+	// {"error":{"msg":"not found","code":"not_found","trace":["batch","bar"],"fields":{"foo":"bar"},"errors":[{"msg":"invalid","code":"invalid","trace":["foo"],"fields":{"foo":"bar"}},{"msg":"not found","code":"not_found","trace":["bar"]}]},"msg":"multiple errors"}
+	// {"error":{"msg":"validation failed","code":"invalid","trace":["github.com/MrEhbr/app/log/gokit.ExampleNew"],"fields":{"violations":[{"field":"name","rule":"required","value":"","message":"name is required"}]}},"msg":"validation error"}
+	// {"error":{"msg":"foo","code":"test_error_code","trace":["test"]},"fingerprint":"d163a4bbf1713acc","msg":"error with fingerprint"}
+}
+
+func ExampleError_logfmt() {
+	logger := log.NewLogfmtLogger(os.Stdout)
+
+	_ = logger.Log("msg", "nil error", "error", Error(nil))
+	_ = logger.Log("msg", "std error", "error", Error(errors.New("foo")))
+	_ = logger.Log("msg", "error with code", "error", Error(&app.Error{Op: "test", Code: app.ETEST, Message: "foo"}))
+	// Output: msg="nil error" error=null
+	// msg="std error" error=foo
+	// msg="error with code" error="test: <test_error_code> foo"
+}
+
+func TestError_stack(t *testing.T) {
+	app.EnableStackCapture(true)
+	defer app.EnableStackCapture(false)
+
+	var buf bytes.Buffer
+	if err := log.NewJSONLogger(&buf).Log("msg", "test", "error", Error(app.ErrorWithCode(errors.New("foo"), app.ETEST))); err != nil {
+		t.Fatal(err)
+	}
+
+	var entry struct {
+		Error struct {
+			Stack []string `json:"stack"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entry.Error.Stack) == 0 {
+		t.Fatalf("stack is not logged: %s", buf.String())
+	}
+
+	const want = "github.com/MrEhbr/app/log/gokit.TestError_stack "
+	if !strings.HasPrefix(entry.Error.Stack[0], want) {
+		t.Fatalf("first frame want prefix: %s, got: %s", want, entry.Error.Stack[0])
+	}
+}
@@ -0,0 +1,62 @@
+package gokit
+
+import (
+	"github.com/MrEhbr/app"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var _ log.Logger = &metricsLogger{}
+
+type metricsLogger struct {
+	next     log.Logger
+	counter  *prometheus.CounterVec
+	errorKey string
+}
+
+// NewErrorMetricsLogger return logger that will send metrics when log error with level.Error and pass keyvals to the next logger
+func NewErrorMetricsLogger(next log.Logger, registry prometheus.Registerer, metricName, errorKey string) log.Logger {
+	return &metricsLogger{
+		next:     next,
+		errorKey: errorKey,
+		counter: promauto.With(registry).NewCounterVec(prometheus.CounterOpts{
+			Name: metricName,
+			Help: "Number of errors grouped by code",
+		}, []string{"code"}),
+	}
+}
+
+func (l *metricsLogger) Log(keyvals ...interface{}) error {
+	if isError(keyvals) {
+		l.count(keyvals)
+	}
+
+	return l.next.Log(keyvals...)
+}
+
+func (l *metricsLogger) count(keyvals []interface{}) {
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		if keyvals[i] != l.errorKey {
+			continue
+		}
+
+		if err, ok := originError(keyvals[i+1]); ok {
+			l.counter.With(prometheus.Labels{"code": app.ErrorCode(err).MetricLabel()}).Inc()
+			return
+		}
+		break
+	}
+
+	l.counter.With(prometheus.Labels{"code": "none"}).Inc()
+}
+
+func isError(keyvals []interface{}) bool {
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		if keyvals[i] == level.Key() && keyvals[i+1] == level.ErrorValue() {
+			return true
+		}
+	}
+	return false
+}
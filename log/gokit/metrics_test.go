@@ -0,0 +1,116 @@
+package gokit
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/MrEhbr/app"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func ExampleNewErrorMetricsLogger() {
+	registry := prometheus.NewRegistry()
+	logger := NewErrorMetricsLogger(log.NewJSONLogger(os.Stdout), registry, "errors", "error")
+
+	_ = level.Error(logger).Log("msg", "test")
+	_ = level.Error(logger).Log("msg", "test", "error", Error(&app.Error{Code: app.ETEST, Message: "test"}))
+	_ = level.Error(log.With(logger, "logger", "sublogger")).Log("msg", "test", "error", Error(&app.Error{Op: "test", Code: app.ETEST, Message: "test"}))
+	// Output: {"level":"error","msg":"test"}
+	// {"error":{"msg":"test","code":"test_error_code","trace":[]},"level":"error","msg":"test"}
+	// {"error":{"msg":"test","code":"test_error_code","trace":["test"]},"level":"error","logger":"sublogger","msg":"test"}
+}
+
+var registerCodeOnce sync.Once
+
+func Test_metricsLogger_Log(t *testing.T) {
+	const (
+		metricName = "errors"
+		errorKey   = "error"
+	)
+	t.Run("metric inc", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		logger := level.Error(NewErrorMetricsLogger(log.NewNopLogger(), registry, metricName, errorKey))
+
+		_ = logger.Log("msg", "test")
+		_ = logger.Log("msg", "test", errorKey, Error(&app.Error{Code: app.ETEST}))
+		_ = logger.Log("msg", "test", errorKey, Error(&app.Error{Code: app.ENOTFOUND}))
+		_ = log.With(logger, "foo", "bar").Log("msg", "test", errorKey, Error(&app.Error{Code: app.ETEST}))
+		_ = log.With(logger, errorKey, &app.Error{Code: app.ECONFLICT}).Log("msg", "test")
+
+		// The last \n at the end of this string is important
+		expected := strings.NewReader(`
+	# HELP errors Number of errors grouped by code
+	# TYPE errors counter
+	errors{code="conflict"} 1
+	errors{code="none"} 1
+	errors{code="not_found"} 1
+	errors{code="test_error_code"} 2
+	`)
+		if err := testutil.GatherAndCompare(registry, expected, metricName); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("custom code", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		logger := level.Error(NewErrorMetricsLogger(log.NewNopLogger(), registry, metricName, errorKey))
+
+		const code app.Code = "gokit_rate_limited"
+		// the registry is global, so the code is registered once with -count=N
+		registerCodeOnce.Do(func() {
+			app.MustRegisterCode(code, app.CodeInfo{Description: "Too many requests"})
+		})
+		_ = logger.Log("msg", "test", errorKey, Error(&app.Error{Code: code}))
+		_ = logger.Log("msg", "test", errorKey, Error(&app.Error{Code: "not_registered"}))
+		_ = logger.Log("msg", "test", errorKey, errors.New("test"))
+
+		// The last \n at the end of this string is important
+		expected := strings.NewReader(`
+	# HELP errors Number of errors grouped by code
+	# TYPE errors counter
+	errors{code="gokit_rate_limited"} 1
+	errors{code="internal"} 1
+	errors{code="unregistered"} 1
+	`)
+		if err := testutil.GatherAndCompare(registry, expected, metricName); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("invalid error value", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		logger := level.Error(NewErrorMetricsLogger(log.NewNopLogger(), registry, metricName, errorKey))
+
+		_ = logger.Log("msg", "test", errorKey, "test")
+
+		// The last \n at the end of this string is important
+		expected := strings.NewReader(`
+	# HELP errors Number of errors grouped by code
+	# TYPE errors counter
+	errors{code="none"} 1
+	`)
+		if err := testutil.GatherAndCompare(registry, expected, metricName); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("don't send metric if level < Error", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		logger := NewErrorMetricsLogger(log.NewNopLogger(), registry, metricName, errorKey)
+
+		_ = level.Info(logger).Log("msg", "test", errorKey, Error(errors.New("test")))
+		_ = logger.Log("msg", "test", errorKey, Error(errors.New("test")))
+
+		n, err := testutil.GatherAndCount(registry, metricName)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if n != 0 {
+			t.Fatalf("expected no metrics gathered, got: %d", n)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package logrus
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/MrEhbr/app"
+	"github.com/MrEhbr/app/internal/errjson"
+	"github.com/sirupsen/logrus"
+)
+
+// err is the error rendered as the structure,
+// it doesn't implement error to not be rendered as a string by logrus.JSONFormatter.
+type err struct {
+	origin error
+}
+
+func (e err) Unwrap() error {
+	return e.origin
+}
+
+// String implements fmt.Stringer, so logrus.TextFormatter renders the error message.
+func (e err) String() string {
+	return e.origin.Error()
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e err) MarshalJSON() ([]byte, error) {
+	return errjson.Marshal(e.origin)
+}
+
+// Error returns the value of the error to be logged with logrus.WithField without ErrorHook.
+func Error(e error) interface{} {
+	if e == nil {
+		return nil
+	}
+	return err{origin: e}
+}
+
+// ErrorHook renders errors of the entry as the structure.
+type ErrorHook struct {
+	// Key of the error in logrus.Fields, logrus.ErrorKey if empty.
+	Key string
+	// FingerprintKey is the key of app.Fingerprint of the error in logrus.Fields,
+	// the fingerprint is not added if empty.
+	FingerprintKey string
+}
+
+// NewErrorHook returns the hook that renders errors logged with logrus.WithError as the structure.
+func NewErrorHook() *ErrorHook {
+	return &ErrorHook{Key: logrus.ErrorKey}
+}
+
+// Levels implements logrus.Hook.
+func (h *ErrorHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements logrus.Hook.
+func (h *ErrorHook) Fire(entry *logrus.Entry) error {
+	key := h.Key
+	if key == "" {
+		key = logrus.ErrorKey
+	}
+
+	switch v := entry.Data[key].(type) {
+	case nil:
+		// skip nil errors the same way as zap does
+		delete(entry.Data, key)
+	case error:
+		entry.Data[key] = err{origin: v}
+		if h.FingerprintKey != "" {
+			entry.Data[h.FingerprintKey] = app.Fingerprint(v)
+		}
+	}
+
+	return nil
+}
+
+// originError returns the error of the value, logged either as is or with Error.
+func originError(v interface{}) (error, bool) {
+	switch e := v.(type) {
+	case err:
+		return e.origin, true
+	case error:
+		return e, true
+	}
+
+	return nil, false
+}
+
+var (
+	_ json.Marshaler = err{}
+	_ fmt.Stringer   = err{}
+	_ logrus.Hook    = &ErrorHook{}
+)
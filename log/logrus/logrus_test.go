@@ -0,0 +1,91 @@
+package logrus
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/MrEhbr/app"
+	"github.com/sirupsen/logrus"
+)
+
+// newExample returns the logger writing to the w in the format close to zap.NewExample.
+func newExample(w io.Writer) *logrus.Logger {
+	log := logrus.New()
+	log.SetOutput(w)
+	log.SetFormatter(&logrus.JSONFormatter{DisableTimestamp: true})
+	log.AddHook(NewErrorHook())
+	return log
+}
+
+func ExampleNew() {
+	log := newExample(os.Stdout)
+
+	log.WithError(nil).Info("nil error")
+	log.WithError(errors.New("foo")).Info("std error")
+	log.WithError(app.ErrorWithCode(errors.New("foo"), app.ETEST)).Info("wrapped std error")
+	log.WithError(&app.Error{Op: "test", Code: app.ETEST, Message: "foo", Fields: map[string]interface{}{"foo": "bar"}}).Info("error with fields")
+	log.WithError(app.OpError("batch", app.Join(
+		&app.Error{Op: "foo", Code: app.EINVALID, Message: "invalid", Fields: map[string]interface{}{"foo": "bar"}},
+		&app.Error{Op: "bar", Code: app.ENOTFOUND, Message: "not found"},
+	))).Info("multiple errors")
+	log.WithError(app.NewValidation().Add("name", "required", "", "name is required").Err()).Info("validation error")
+	log.WithField("cause", Error(&app.Error{Op: "test", Code: app.ETEST, Message: "foo"})).Info("custom key")
+	// Output: {"level":"info","msg":"nil error"}
+	// {"error":{"msg":"foo"},"level":"info","msg":"std error"}
+	// {"error":{"msg":"foo","code":"test_error_code","trace":["github.com/MrEhbr/app/log/logrus.ExampleNew"]},"level":"info","msg":"wrapped std error"}
+	// {"error":{"msg":"foo","code":"test_error_code","trace":["test"],"fields":{"foo":"bar"}},"level":"info","msg":"error with fields"}
+	// {"error":{"msg":"not found","code":"not_found","trace":["batch","bar"],"fields":{"foo":"bar"},"errors":[{"msg":"invalid","code":"invalid","trace":["foo"],"fields":{"foo":"bar"}},{"msg":"not found","code":"not_found","trace":["bar"]}]},"level":"info","msg":"multiple errors"}
+	// {"error":{"msg":"validation failed","code":"invalid","trace":["github.com/MrEhbr/app/log/logrus.ExampleNew"],"fields":{"violations":[{"field":"name","rule":"required","value":"","message":"name is required"}]}},"level":"info","msg":"validation error"}
+	// {"cause":{"msg":"foo","code":"test_error_code","trace":["test"]},"level":"info","msg":"custom key"}
+}
+
+func TestErrorHook_stack(t *testing.T) {
+	app.EnableStackCapture(true)
+	defer app.EnableStackCapture(false)
+
+	var buf bytes.Buffer
+	newExample(&buf).WithError(app.ErrorWithCode(errors.New("foo"), app.ETEST)).Info("test")
+
+	var entry struct {
+		Error struct {
+			Stack []string `json:"stack"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entry.Error.Stack) == 0 {
+		t.Fatalf("stack is not logged: %s", buf.String())
+	}
+
+	const want = "github.com/MrEhbr/app/log/logrus.TestErrorHook_stack "
+	if !strings.HasPrefix(entry.Error.Stack[0], want) {
+		t.Fatalf("first frame want prefix: %s, got: %s", want, entry.Error.Stack[0])
+	}
+}
+
+func ExampleErrorHook_text() {
+	log := logrus.New()
+	log.SetOutput(os.Stdout)
+	log.SetFormatter(&logrus.TextFormatter{DisableTimestamp: true})
+	log.AddHook(NewErrorHook())
+
+	log.WithError(&app.Error{Op: "test", Code: app.ETEST, Message: "foo"}).Info("text formatter")
+	// Output: level=info msg="text formatter" error="test: <test_error_code> foo"
+}
+
+func ExampleErrorHook_fingerprint() {
+	log := logrus.New()
+	log.SetOutput(os.Stdout)
+	log.SetFormatter(&logrus.JSONFormatter{DisableTimestamp: true})
+	log.AddHook(&ErrorHook{FingerprintKey: "fingerprint"})
+
+	log.WithError(&app.Error{Op: "test", Code: app.ETEST, Message: "foo"}).Info("error with fingerprint")
+	// Output: {"error":{"msg":"foo","code":"test_error_code","trace":["test"]},"fingerprint":"d163a4bbf1713acc","level":"info","msg":"error with fingerprint"}
+}
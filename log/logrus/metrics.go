@@ -0,0 +1,42 @@
+package logrus
+
+import (
+	"github.com/MrEhbr/app"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sirupsen/logrus"
+)
+
+var _ logrus.Hook = &metricsHook{}
+
+type metricsHook struct {
+	counter  *prometheus.CounterVec
+	errorKey string
+}
+
+// NewErrorMetricsHook return hook that will send metrics when log error
+func NewErrorMetricsHook(registry prometheus.Registerer, metricName, errorKey string) logrus.Hook {
+	return &metricsHook{
+		errorKey: errorKey,
+		counter: promauto.With(registry).NewCounterVec(prometheus.CounterOpts{
+			Name: metricName,
+			Help: "Number of errors grouped by code",
+		}, []string{"code"}),
+	}
+}
+
+func (h *metricsHook) Levels() []logrus.Level {
+	return []logrus.Level{logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel}
+}
+
+func (h *metricsHook) Fire(entry *logrus.Entry) error {
+	err, ok := originError(entry.Data[h.errorKey])
+	if !ok {
+		h.counter.With(prometheus.Labels{"code": "none"}).Inc()
+		return nil
+	}
+
+	h.counter.With(prometheus.Labels{"code": app.ErrorCode(err).MetricLabel()}).Inc()
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package logrus
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/MrEhbr/app"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+)
+
+func ExampleNewErrorMetricsHook() {
+	registry := prometheus.NewRegistry()
+	log := newExample(os.Stdout)
+	log.AddHook(NewErrorMetricsHook(registry, "errors", logrus.ErrorKey))
+
+	log.Error("test")
+	log.WithError(&app.Error{Code: app.ETEST, Message: "test"}).Error("test")
+	log.WithField("logger", "sublogger").WithError(&app.Error{Op: "test", Code: app.ETEST, Message: "test"}).Error("test")
+	// Output: {"level":"error","msg":"test"}
+	// {"error":{"msg":"test","code":"test_error_code","trace":[]},"level":"error","msg":"test"}
+	// {"error":{"msg":"test","code":"test_error_code","trace":["test"]},"level":"error","logger":"sublogger","msg":"test"}
+}
+
+var registerCodeOnce sync.Once
+
+func Test_metricsHook_Fire(t *testing.T) {
+	const (
+		metricName = "errors"
+		errorKey   = "error"
+	)
+	newLogger := func(registry prometheus.Registerer) *logrus.Logger {
+		log := newExample(io.Discard)
+		log.AddHook(NewErrorMetricsHook(registry, metricName, errorKey))
+		return log
+	}
+
+	t.Run("metric inc", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		log := newLogger(registry)
+
+		log.Error("test")
+		log.WithError(&app.Error{Code: app.ETEST}).Error("test")
+		log.WithError(&app.Error{Code: app.ENOTFOUND}).Error("test")
+		log.WithField("foo", "bar").WithError(&app.Error{Code: app.ETEST}).Error("test")
+		log.WithField(errorKey, Error(&app.Error{Code: app.ECONFLICT})).Error("test")
+
+		// The last \n at the end of this string is important
+		expected := strings.NewReader(`
+	# HELP errors Number of errors grouped by code
+	# TYPE errors counter
+	errors{code="conflict"} 1
+	errors{code="none"} 1
+	errors{code="not_found"} 1
+	errors{code="test_error_code"} 2
+	`)
+		if err := testutil.GatherAndCompare(registry, expected, metricName); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("custom code", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		log := newLogger(registry)
+
+		const code app.Code = "logrus_rate_limited"
+		// the registry is global, so the code is registered once with -count=N
+		registerCodeOnce.Do(func() {
+			app.MustRegisterCode(code, app.CodeInfo{Description: "Too many requests"})
+		})
+		log.WithError(&app.Error{Code: code}).Error("test")
+		log.WithError(&app.Error{Code: "not_registered"}).Error("test")
+		log.WithError(errors.New("test")).Error("test")
+
+		// The last \n at the end of this string is important
+		expected := strings.NewReader(`
+	# HELP errors Number of errors grouped by code
+	# TYPE errors counter
+	errors{code="internal"} 1
+	errors{code="logrus_rate_limited"} 1
+	errors{code="unregistered"} 1
+	`)
+		if err := testutil.GatherAndCompare(registry, expected, metricName); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("invalid error field", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		log := newLogger(registry)
+
+		log.WithField(errorKey, "test").Error("test")
+
+		// The last \n at the end of this string is important
+		expected := strings.NewReader(`
+	# HELP errors Number of errors grouped by code
+	# TYPE errors counter
+	errors{code="none"} 1
+	`)
+		if err := testutil.GatherAndCompare(registry, expected, metricName); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("don't send metric if level < Error", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		log := newLogger(registry)
+
+		log.WithError(errors.New("test")).Info("test")
+
+		n, err := testutil.GatherAndCount(registry, metricName)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if n != 0 {
+			t.Fatalf("expected no metrics gathered, got: %d", n)
+		}
+	})
+}
@@ -0,0 +1,102 @@
+package slog
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/MrEhbr/app"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var _ slog.Handler = &metricsHandler{}
+
+type metricsHandler struct {
+	next     slog.Handler
+	counter  *prometheus.CounterVec
+	errorKey string
+	// error from the attributes added with WithAttrs
+	err error
+	// whether WithAttrs adds attributes to a group
+	grouped bool
+}
+
+// NewErrorMetricsHandler return handler that will send metrics when log error and pass records to the next handler
+func NewErrorMetricsHandler(next slog.Handler, registry prometheus.Registerer, metricName, errorKey string) slog.Handler {
+	return &metricsHandler{
+		next:     next,
+		errorKey: errorKey,
+		counter: promauto.With(registry).NewCounterVec(prometheus.CounterOpts{
+			Name: metricName,
+			Help: "Number of errors grouped by code",
+		}, []string{"code"}),
+	}
+}
+
+func (h *metricsHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= slog.LevelError || h.next.Enabled(ctx, level)
+}
+
+func (h *metricsHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level >= slog.LevelError {
+		h.count(r)
+	}
+
+	if !h.next.Enabled(ctx, r.Level) {
+		return nil
+	}
+
+	return h.next.Handle(ctx, r)
+}
+
+func (h *metricsHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := *h
+	c.next = h.next.WithAttrs(attrs)
+	if !h.grouped {
+		if err, ok := findError(h.errorKey, attrs); ok {
+			c.err = err
+		}
+	}
+
+	return &c
+}
+
+func (h *metricsHandler) WithGroup(name string) slog.Handler {
+	c := *h
+	c.next = h.next.WithGroup(name)
+	if name != "" {
+		c.grouped = true
+	}
+
+	return &c
+}
+
+func (h *metricsHandler) count(r slog.Record) {
+	err := h.err
+	if !h.grouped {
+		r.Attrs(func(attr slog.Attr) bool {
+			if e, ok := findError(h.errorKey, []slog.Attr{attr}); ok {
+				err = e
+				return false
+			}
+			return true
+		})
+	}
+
+	if err == nil {
+		h.counter.With(prometheus.Labels{"code": "none"}).Inc()
+		return
+	}
+
+	h.counter.With(prometheus.Labels{"code": app.ErrorCode(err).MetricLabel()}).Inc()
+}
+
+func findError(key string, attrs []slog.Attr) (error, bool) {
+	for _, attr := range attrs {
+		if attr.Key == key {
+			err, ok := attr.Value.Any().(error)
+			return err, ok
+		}
+	}
+	return nil, false
+}
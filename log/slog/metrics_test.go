@@ -0,0 +1,121 @@
+package slog
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/MrEhbr/app"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func ExampleNewErrorMetricsHandler() {
+	registry := prometheus.NewRegistry()
+	log := slog.New(NewErrorMetricsHandler(newExample(os.Stdout).Handler(), registry, "errors", "error"))
+
+	log.Error("test")
+	log.Error("test", Error(&app.Error{Code: app.ETEST, Message: "test"}))
+	log.With(slog.String("logger", "sublogger")).Error("test", Error(&app.Error{Op: "test", Code: app.ETEST, Message: "test"}))
+	// Output: {"level":"error","msg":"test"}
+	// {"level":"error","msg":"test","error":{"msg":"test","code":"test_error_code","trace":[]}}
+	// {"level":"error","msg":"test","logger":"sublogger","error":{"msg":"test","code":"test_error_code","trace":["test"]}}
+}
+
+var registerCodeOnce sync.Once
+
+func Test_metricsHandler_Handle(t *testing.T) {
+	const (
+		metricName = "errors"
+		errorKey   = "error"
+	)
+	newLogger := func(registry prometheus.Registerer) *slog.Logger {
+		return slog.New(NewErrorMetricsHandler(slog.NewJSONHandler(io.Discard, nil), registry, metricName, errorKey))
+	}
+
+	t.Run("metric inc", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		log := newLogger(registry)
+
+		log.Error("test")
+		log.Error("test", Error(&app.Error{Code: app.ETEST}))
+		log.Error("test", Error(&app.Error{Code: app.ENOTFOUND}))
+		log.With(slog.String("foo", "bar")).Error("test", Error(&app.Error{Code: app.ETEST}))
+		log.With(Error(&app.Error{Code: app.ECONFLICT})).Error("test")
+		log.With(Error(&app.Error{Code: app.ECONFLICT})).Error("test", Error(&app.Error{Code: app.ETEST}))
+
+		// The last \n at the end of this string is important
+		expected := strings.NewReader(`
+	# HELP errors Number of errors grouped by code
+	# TYPE errors counter
+	errors{code="conflict"} 1
+	errors{code="none"} 1
+	errors{code="not_found"} 1
+	errors{code="test_error_code"} 3
+	`)
+		if err := testutil.GatherAndCompare(registry, expected, metricName); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("custom code", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		log := newLogger(registry)
+
+		const code app.Code = "slog_rate_limited"
+		// the registry is global, so the code is registered once with -count=N
+		registerCodeOnce.Do(func() {
+			app.MustRegisterCode(code, app.CodeInfo{Description: "Too many requests"})
+		})
+		log.Error("test", Error(&app.Error{Code: code}))
+		log.Error("test", Error(&app.Error{Code: "not_registered"}))
+		log.Error("test", slog.Any(errorKey, errors.New("test")))
+
+		// The last \n at the end of this string is important
+		expected := strings.NewReader(`
+	# HELP errors Number of errors grouped by code
+	# TYPE errors counter
+	errors{code="internal"} 1
+	errors{code="slog_rate_limited"} 1
+	errors{code="unregistered"} 1
+	`)
+		if err := testutil.GatherAndCompare(registry, expected, metricName); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("invalid error attribute", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		log := newLogger(registry)
+
+		log.Error("test", slog.String(errorKey, "test"))
+		log.WithGroup("group").Error("test", Error(&app.Error{Code: app.ETEST}))
+
+		// The last \n at the end of this string is important
+		expected := strings.NewReader(`
+	# HELP errors Number of errors grouped by code
+	# TYPE errors counter
+	errors{code="none"} 2
+	`)
+		if err := testutil.GatherAndCompare(registry, expected, metricName); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("don't send metric if level < Error", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		log := newLogger(registry)
+
+		log.Info("test", Error(errors.New("test")))
+
+		n, err := testutil.GatherAndCount(registry, metricName)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if n != 0 {
+			t.Fatalf("expected no metrics gathered, got: %d", n)
+		}
+	})
+}
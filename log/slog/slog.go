@@ -0,0 +1,162 @@
+package slog
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sort"
+
+	"github.com/MrEhbr/app"
+)
+
+type err struct {
+	origin error
+}
+
+func (e err) Error() string {
+	return e.origin.Error()
+}
+
+func (e err) Unwrap() error {
+	return e.origin
+}
+
+// LogValue implements slog.LogValuer.
+func (e err) LogValue() slog.Value {
+	if e.origin == nil {
+		return slog.GroupValue()
+	}
+
+	if !errors.Is(e.origin, &app.Error{}) {
+		return slog.GroupValue(slog.String("msg", e.origin.Error()))
+	}
+
+	trace := app.ErrorTrace(e.origin)
+	if trace == nil {
+		trace = []string{}
+	}
+
+	attrs := []slog.Attr{
+		slog.String("msg", app.ErrorMessageDefault(e.origin, "")),
+	}
+	if detail := app.ErrorDetail(e.origin); detail != "" {
+		attrs = append(attrs, slog.String("detail", detail))
+	}
+	attrs = append(attrs,
+		slog.String("code", app.ErrorCode(e.origin).String()),
+		slog.Any("trace", trace),
+	)
+	if fields := app.RedactedErrorFields(e.origin); len(fields) > 0 {
+		attrs = append(attrs, slog.Attr{Key: "fields", Value: fieldsValue(fields)})
+	}
+	var errs app.Errors
+	if errors.As(e.origin, &errs) {
+		attrs = append(attrs, slog.Any("errors", errArr(errs)))
+	}
+	if stack := app.ErrorStack(e.origin); len(stack) > 0 {
+		attrs = append(attrs, slog.Any("stack", stack.Strings()))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// MarshalJSON implements json.Marshaler, used when the error is nested in an attribute value.
+func (e err) MarshalJSON() ([]byte, error) {
+	return marshalValue(e.LogValue())
+}
+
+func fieldsValue(fields map[string]interface{}) slog.Value {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, slog.Any(k, fields[k]))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// errArr is the list of app.Errors, slog has no array values, so it's marshaled by handlers directly.
+type errArr []error
+
+func (a errArr) MarshalJSON() ([]byte, error) {
+	items := make([]json.RawMessage, 0, len(a))
+	for _, e := range a {
+		if e == nil {
+			continue
+		}
+		data, merr := err{origin: e}.MarshalJSON()
+		if merr != nil {
+			return nil, merr
+		}
+		items = append(items, data)
+	}
+
+	return json.Marshal(items)
+}
+
+// marshalValue marshals the value keeping the order of group attributes.
+func marshalValue(v slog.Value) ([]byte, error) {
+	v = v.Resolve()
+	if v.Kind() != slog.KindGroup {
+		return json.Marshal(v.Any())
+	}
+
+	buf := []byte{'{'}
+	for i, a := range v.Group() {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		key, kerr := json.Marshal(a.Key)
+		if kerr != nil {
+			return nil, kerr
+		}
+		val, verr := marshalValue(a.Value)
+		if verr != nil {
+			return nil, verr
+		}
+		buf = append(buf, key...)
+		buf = append(buf, ':')
+		buf = append(buf, val...)
+	}
+
+	return append(buf, '}'), nil
+}
+
+// NamedError returns the attribute of the error with the key, the attribute is empty if the error is nil.
+func NamedError(key string, e error) slog.Attr {
+	if e == nil {
+		return slog.Attr{}
+	}
+	return slog.Any(key, err{origin: e})
+}
+
+// Error returns the attribute of the error with the "error" key.
+func Error(err error) slog.Attr {
+	return NamedError("error", err)
+}
+
+// FingerprintKey is the key of the fingerprint attribute.
+const FingerprintKey = "fingerprint"
+
+// Fingerprint returns the attribute of app.Fingerprint of the error, the attribute is empty if the error is nil.
+func Fingerprint(err error, opts ...app.FingerprintOption) slog.Attr {
+	if err == nil {
+		return slog.Attr{}
+	}
+	return slog.String(FingerprintKey, app.Fingerprint(err, opts...))
+}
+
+// Value returns slog.LogValuer of the error, e.g. to log the error with slog.Any.
+func Value(e error) slog.LogValuer {
+	return err{origin: e}
+}
+
+var (
+	_ slog.LogValuer = err{}
+	_ json.Marshaler = err{}
+)
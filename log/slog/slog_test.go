@@ -0,0 +1,85 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/MrEhbr/app"
+)
+
+// newExample returns the logger writing to the w in the format of zap.NewExample.
+func newExample(w *os.File) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{ReplaceAttr: replaceExampleAttr}))
+}
+
+func replaceExampleAttr(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) > 0 {
+		return a
+	}
+
+	switch a.Key {
+	case slog.TimeKey:
+		return slog.Attr{}
+	case slog.LevelKey:
+		return slog.String(slog.LevelKey, strings.ToLower(a.Value.String()))
+	}
+
+	return a
+}
+
+func ExampleNew() {
+	log := newExample(os.Stdout)
+
+	log.Info("nil error", Error(nil))
+	log.Info("std error", Error(errors.New("foo")))
+	log.Info("wrapped std error", Error(app.ErrorWithCode(errors.New("foo"), app.ETEST)))
+	log.Info("error with fields", Error(&app.Error{Op: "test", Code: app.ETEST, Message: "foo", Fields: map[string]interface{}{"foo": "bar"}}))
+	log.Info("multiple errors", Error(app.OpError("batch", app.Join(
+		&app.Error{Op: "foo", Code: app.EINVALID, Message: "invalid", Fields: map[string]interface{}{"foo": "bar"}},
+		&app.Error{Op: "bar", Code: app.ENOTFOUND, Message: "not found"},
+	))))
+	log.Info("validation error", Error(app.NewValidation().Add("name", "required", "", "name is required").Err()))
+	log.Info("log valuer", slog.Any("error", Value(&app.Error{Op: "test", Code: app.ETEST, Message: "foo"})))
+	fpErr := &app.Error{Op: "test", Code: app.ETEST, Message: "foo"}
+	log.Info("error with fingerprint", Error(fpErr), Fingerprint(fpErr))
+	// Output: {"level":"info","msg":"nil error"}
+	// {"level":"info","msg":"std error","error":{"msg":"foo"}}
+	// {"level":"info","msg":"wrapped std error","error":{"msg":"foo","code":"test_error_code","trace":["github.com/MrEhbr/app/log/slog.ExampleNew"]}}
+	// {"level":"info","msg":"error with fields","error":{"msg":"foo","code":"test_error_code","trace":["test"],"fields":{"foo":"bar"}}}
+	// {"level":"info","msg":"multiple errors","error":{"msg":"not found","code":"not_found","trace":["batch","bar"],"fields":{"foo":"bar"},"errors":[{"msg":"invalid","code":"invalid","trace":["foo"],"fields":{"foo":"bar"}},{"msg":"not found","code":"not_found","trace":["bar"]}]}}
+	// {"level":"info","msg":"validation error","error":{"msg":"validation failed","code":"invalid","trace":["github.com/MrEhbr/app/log/slog.ExampleNew"],"fields":{"violations":[{"field":"name","rule":"required","value":"","message":"name is required"}]}}}
+	// {"level":"info","msg":"log valuer","error":{"msg":"foo","code":"test_error_code","trace":["test"]}}
+	// {"level":"info","msg":"error with fingerprint","error":{"msg":"foo","code":"test_error_code","trace":["test"]},"fingerprint":"d163a4bbf1713acc"}
+}
+
+func TestError_stack(t *testing.T) {
+	app.EnableStackCapture(true)
+	defer app.EnableStackCapture(false)
+
+	var buf bytes.Buffer
+	log := slog.New(slog.NewJSONHandler(&buf, nil))
+	log.Info("test", Error(app.ErrorWithCode(errors.New("foo"), app.ETEST)))
+
+	var entry struct {
+		Error struct {
+			Stack []string `json:"stack"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entry.Error.Stack) == 0 {
+		t.Fatalf("stack is not logged: %s", buf.String())
+	}
+
+	const want = "github.com/MrEhbr/app/log/slog.TestError_stack "
+	if !strings.HasPrefix(entry.Error.Stack[0], want) {
+		t.Fatalf("first frame want prefix: %s, got: %s", want, entry.Error.Stack[0])
+	}
+}
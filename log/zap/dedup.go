@@ -0,0 +1,141 @@
+package zap
+
+import (
+	"errors"
+	"time"
+
+	"github.com/MrEhbr/app"
+	"github.com/MrEhbr/app/internal/dedup"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// SuppressedKey is the key of the number of suppressed entries in the summary entry.
+	SuppressedKey = "suppressed"
+
+	// DefaultDedupWindow is the default window of deduplication.
+	DefaultDedupWindow = dedup.DefaultWindow
+	// DefaultDedupFirst is the default number of entries logged per fingerprint within the window.
+	DefaultDedupFirst = dedup.DefaultFirst
+)
+
+// DedupOption configures the deduplication.
+type DedupOption func(*dedup.Options)
+
+// WithDedupWindow sets the window of deduplication.
+func WithDedupWindow(window time.Duration) DedupOption {
+	return func(o *dedup.Options) {
+		o.Window = window
+	}
+}
+
+// WithDedupFirst sets the number of entries logged per fingerprint within the window.
+func WithDedupFirst(n int) DedupOption {
+	return func(o *dedup.Options) {
+		o.First = n
+	}
+}
+
+// WithDedupClock sets the clock, e.g. for tests.
+func WithDedupClock(now func() time.Time) DedupOption {
+	return func(o *dedup.Options) {
+		o.Now = now
+	}
+}
+
+// dedupEntry is the suppressed entry and the core it was written to.
+type dedupEntry struct {
+	core   zapcore.Core
+	entry  zapcore.Entry
+	fields []zapcore.Field
+}
+
+type dedupCore struct {
+	zapcore.Core
+	window   *dedup.Window[dedupEntry]
+	errorKey string
+	// fields added with With
+	fields []zapcore.Field
+}
+
+// NewDedupCore returns core that deduplicates entries with app errors logged under the errorKey by app.Fingerprint.
+// Within the window, the first entries of each fingerprint are written to the core and the rest are suppressed.
+// When the window is over, the last suppressed entry is written with the SuppressedKey field
+// holding the number of suppressed entries. Summaries are written by following entries or on Sync.
+//
+// Tee the core with NewErrorMetricsCore to count every error, including suppressed ones.
+func NewDedupCore(core zapcore.Core, errorKey string, opts ...DedupOption) zapcore.Core {
+	return &dedupCore{
+		Core:     core,
+		errorKey: errorKey,
+		window:   dedup.NewWindow(dedup.NewOptions(opts), cloneDedupEntry),
+	}
+}
+
+func (c *dedupCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.Core = c.Core.With(fields)
+	// copy the fields, so child cores don't share the backing array
+	clone.fields = make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	clone.fields = append(clone.fields, c.fields...)
+	clone.fields = append(clone.fields, fields...)
+
+	return &clone
+}
+
+func (c *dedupCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+
+	return ce
+}
+
+func (c *dedupCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	all := fields
+	if len(c.fields) > 0 {
+		all = make([]zapcore.Field, 0, len(c.fields)+len(fields))
+		all = append(all, c.fields...)
+		all = append(all, fields...)
+	}
+
+	errField, ok := haveAppError(c.errorKey, all)
+	if !ok || !errors.Is(errField.Interface.(error), &app.Error{}) {
+		return c.Core.Write(entry, fields)
+	}
+
+	write, summaries := c.window.Observe(app.Fingerprint(errField.Interface.(error)), dedupEntry{core: c.Core, entry: entry, fields: fields})
+	if err := writeSummaries(summaries); err != nil {
+		return err
+	}
+	if !write {
+		return nil
+	}
+
+	return c.Core.Write(entry, fields)
+}
+
+func (c *dedupCore) Sync() error {
+	if err := writeSummaries(c.window.Flush()); err != nil {
+		return err
+	}
+
+	return c.Core.Sync()
+}
+
+func cloneDedupEntry(e dedupEntry) dedupEntry {
+	e.fields = append([]zapcore.Field(nil), e.fields...)
+	return e
+}
+
+func writeSummaries(summaries []dedup.Summary[dedupEntry]) error {
+	for _, s := range summaries {
+		fields := append(s.Entry.fields[:len(s.Entry.fields):len(s.Entry.fields)], zap.Int(SuppressedKey, s.Suppressed))
+		if err := s.Entry.core.Write(s.Entry.entry, fields); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package zap
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MrEhbr/app"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func ExampleNewDedupCore() {
+	now := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	log := zap.NewExample(zap.WrapCore(func(origin zapcore.Core) zapcore.Core {
+		return NewDedupCore(origin, "error", WithDedupFirst(2), WithDedupWindow(time.Minute), WithDedupClock(func() time.Time { return now }))
+	}))
+
+	err := &app.Error{Op: "query", Code: app.EINTERNAL, Message: "db is down"}
+	for i := 0; i < 5; i++ {
+		log.Error("query failed", Error(err))
+	}
+	log.Error("std error", Error(errors.New("foo")))
+	log.Error("other error", Error(&app.Error{Op: "publish", Code: app.EINTERNAL, Message: "broker is down"}))
+
+	now = now.Add(time.Minute)
+	log.Error("query failed", Error(err))
+	// Output: {"level":"error","msg":"query failed","error":{"msg":"db is down","code":"internal","trace":["query"]}}
+	// {"level":"error","msg":"query failed","error":{"msg":"db is down","code":"internal","trace":["query"]}}
+	// {"level":"error","msg":"std error","error":{"msg":"foo"}}
+	// {"level":"error","msg":"other error","error":{"msg":"broker is down","code":"internal","trace":["publish"]}}
+	// {"level":"error","msg":"query failed","error":{"msg":"db is down","code":"internal","trace":["query"]},"suppressed":3}
+	// {"level":"error","msg":"query failed","error":{"msg":"db is down","code":"internal","trace":["query"]}}
+}
+
+func TestDedupCore(t *testing.T) {
+	now := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	t.Run("metrics count suppressed", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		observed, logs := observer.New(zap.DebugLevel)
+		log := zap.New(zapcore.NewTee(
+			NewDedupCore(observed, "error", WithDedupFirst(1), WithDedupClock(clock)),
+			NewErrorMetricsCore(registry, "errors", "error"),
+		))
+
+		for i := 0; i < 5; i++ {
+			log.Error("test", Error(app.NewError(app.EINTERNAL, "foo")))
+		}
+
+		if logs.Len() != 1 {
+			t.Fatalf("logs want: 1, got: %d", logs.Len())
+		}
+
+		const want = `
+# HELP errors Number of errors grouped by code
+# TYPE errors counter
+errors{code="internal"} 5
+`
+		if err := testutil.GatherAndCompare(registry, strings.NewReader(want), "errors"); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("child loggers share state", func(t *testing.T) {
+		observed, logs := observer.New(zap.DebugLevel)
+		log := zap.New(NewDedupCore(observed, "error", WithDedupFirst(1), WithDedupClock(clock)))
+		err := &app.Error{Op: "test", Code: app.EINTERNAL}
+
+		log.Error("test", Error(err))
+		log.With(zap.String("foo", "bar")).Error("test", Error(err))
+		log.With(Error(err)).Error("test")
+
+		if logs.Len() != 1 {
+			t.Fatalf("logs want: 1, got: %d", logs.Len())
+		}
+	})
+
+	t.Run("sync writes summaries", func(t *testing.T) {
+		observed, logs := observer.New(zap.DebugLevel)
+		log := zap.New(NewDedupCore(observed, "error", WithDedupFirst(1), WithDedupClock(clock)))
+		err := &app.Error{Op: "test", Code: app.EINTERNAL}
+
+		for i := 0; i < 3; i++ {
+			log.With(zap.Int("i", i)).Error("test", Error(err))
+		}
+		if err := log.Sync(); err != nil {
+			t.Fatal(err)
+		}
+		log.Error("test", Error(err))
+
+		entries := logs.All()
+		if len(entries) != 2 {
+			t.Fatalf("logs want: 2, got: %d", len(entries))
+		}
+
+		want := map[string]interface{}{"i": int64(2), SuppressedKey: int64(2)}
+		got := entries[1].ContextMap()
+		delete(got, "error")
+		for k, v := range want {
+			if got[k] != v {
+				t.Fatalf("summary field %s want: %v, got: %v", k, v, got[k])
+			}
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package zap
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/MrEhbr/app"
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// gatherOpenMetrics returns metrics of the registry in the OpenMetrics text format without exemplar timestamps.
+func gatherOpenMetrics(t *testing.T, registry *prometheus.Registry) string {
+	t.Helper()
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	for _, family := range families {
+		for _, metric := range family.Metric {
+			if metric.Counter != nil && metric.Counter.Exemplar != nil {
+				metric.Counter.Exemplar.Timestamp = nil
+			}
+		}
+		if _, err := expfmt.MetricFamilyToOpenMetrics(&buf, family); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := expfmt.FinalizeOpenMetrics(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.String()
+}
+
+func Test_metricCore_exemplars(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	log := zap.NewNop().WithOptions(zap.WrapCore(func(origin zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(origin, NewErrorMetricsCoreWithOptions(registry, "errors_total", "error",
+			WithExemplars("trace_id"),
+			WithFingerprints("error_fingerprints_total"),
+		))
+	}))
+
+	log.Error("test", Error(app.OpError("users.Get", &app.Error{Code: app.ENOTFOUND})), zap.String("trace_id", "abc"))
+	log.With(zap.String("trace_id", "def")).Error("test", Error(app.OpError("users.Get", &app.Error{Code: app.ENOTFOUND})))
+	log.Error("test", Error(app.OpError("users.List", &app.Error{Code: app.ENOTFOUND})))
+	log.Error("test", Error(&app.Error{Op: "users.Get", Code: app.ETEST}), zap.String("trace_id", "ghi"))
+	log.Error("test")
+
+	want := `# HELP error_fingerprints Number of first-seen error fingerprints grouped by code
+# TYPE error_fingerprints counter
+error_fingerprints_total{code="not_found"} 2.0
+error_fingerprints_total{code="test_error_code"} 1.0
+# HELP errors Number of errors grouped by code
+# TYPE errors counter
+errors_total{code="none"} 1.0
+errors_total{code="not_found"} 3.0 # {trace_id="def"} 1.0
+errors_total{code="test_error_code"} 1.0 # {trace_id="ghi"} 1.0
+# EOF
+`
+	if diff := cmp.Diff(want, gatherOpenMetrics(t, registry)); diff != "" {
+		t.Fatalf("metrics mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_metricCore_fingerprintsLimit(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	log := zap.New(NewErrorMetricsCoreWithOptions(registry, "errors_total", "error",
+		WithFingerprints("error_fingerprints_total"),
+		WithFingerprintsLimit(1),
+	))
+
+	// users.Get is forgotten when users.List is seen, so it's counted again
+	for _, op := range []string{"users.Get", "users.Get", "users.List", "users.Get"} {
+		log.Error("test", Error(app.OpError(op, &app.Error{Code: app.ENOTFOUND})))
+	}
+
+	want := `# HELP error_fingerprints Number of first-seen error fingerprints grouped by code
+# TYPE error_fingerprints counter
+error_fingerprints_total{code="not_found"} 3.0
+# HELP errors Number of errors grouped by code
+# TYPE errors counter
+errors_total{code="not_found"} 4.0
+# EOF
+`
+	if diff := cmp.Diff(want, gatherOpenMetrics(t, registry)); diff != "" {
+		t.Fatalf("metrics mismatch (-want +got):\n%s", diff)
+	}
+}
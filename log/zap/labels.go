@@ -0,0 +1,56 @@
+package zap
+
+import "github.com/MrEhbr/app/internal/metrics"
+
+// DefaultMaxLabelValues is the default number of distinct values of a metric label.
+const DefaultMaxLabelValues = metrics.DefaultMaxLabelValues
+
+type metricsOptions struct {
+	labels    []Label
+	maxValues int
+	allowed   map[string][]string
+
+	exemplarKey        string
+	fingerprintsMetric string
+	fingerprintsLimit  int
+}
+
+// MetricsOption configures the error metrics.
+type MetricsOption func(*metricsOptions)
+
+// WithLabels sets labels of the metric.
+func WithLabels(labels ...Label) MetricsOption {
+	return func(o *metricsOptions) {
+		o.labels = labels
+	}
+}
+
+// WithMaxLabelValues sets the number of distinct values of each label, values above it are collapsed into "other".
+// Not positive n means no limit.
+func WithMaxLabelValues(n int) MetricsOption {
+	return func(o *metricsOptions) {
+		o.maxValues = n
+	}
+}
+
+// WithLabelValues sets the allowed values of the label, unseen values are collapsed into "other".
+func WithLabelValues(name string, values ...string) MetricsOption {
+	return func(o *metricsOptions) {
+		o.allowed[name] = values
+	}
+}
+
+func newMetricsOptions(opts []MetricsOption) metricsOptions {
+	o := metricsOptions{
+		labels:    []Label{CodeLabel()},
+		maxValues: DefaultMaxLabelValues,
+		allowed:   map[string][]string{},
+
+		fingerprintsLimit: DefaultFingerprintsLimit,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
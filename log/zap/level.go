@@ -0,0 +1,61 @@
+package zap
+
+import (
+	"github.com/MrEhbr/app"
+	"github.com/MrEhbr/app/internal/mapx"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Levels overrides log levels of error codes from app.CodeInfo.
+type Levels map[app.Code]zapcore.Level
+
+// With returns a copy of l extended with overrides.
+func (l Levels) With(overrides Levels) Levels {
+	return mapx.Merge(l, overrides)
+}
+
+// Level returns the log level of the err.
+// If err is nil returns zap.InfoLevel,
+// if the code is neither in l nor registered with LogLevel returns zap.ErrorLevel.
+func (l Levels) Level(err error) zapcore.Level {
+	if err == nil {
+		return zap.InfoLevel
+	}
+
+	code := app.ErrorCode(err)
+	if level, ok := l[code]; ok {
+		return level
+	}
+
+	if info, ok := app.LookupCode(code); ok && info.LogLevel != "" {
+		if level, err := zapcore.ParseLevel(info.LogLevel); err == nil {
+			return level
+		}
+	}
+
+	return zap.ErrorLevel
+}
+
+// LogError logs the err with the msg and the fields at the level of the err,
+// so client errors like app.ENOTFOUND aren't counted by NewErrorMetricsCore.
+func (l Levels) LogError(logger *zap.Logger, msg string, err error, fields ...zap.Field) {
+	l.logError(logger, msg, err, fields)
+}
+
+func (l Levels) logError(logger *zap.Logger, msg string, err error, fields []zap.Field) {
+	level := l.Level(err)
+	if !logger.Core().Enabled(level) {
+		return
+	}
+
+	// skip LogError and logError
+	if ce := logger.WithOptions(zap.AddCallerSkip(2)).Check(level, msg); ce != nil {
+		ce.Write(append(fields[:len(fields):len(fields)], Error(err))...)
+	}
+}
+
+// LogError logs the err with the msg and the fields at the level of the err using the zero Levels.
+func LogError(logger *zap.Logger, msg string, err error, fields ...zap.Field) {
+	Levels{}.logError(logger, msg, err, fields)
+}
@@ -0,0 +1,89 @@
+package zap
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/MrEhbr/app"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func ExampleLogError() {
+	log := zap.NewExample()
+
+	LogError(log, "not found", &app.Error{Op: "test", Code: app.ENOTFOUND, Message: "user not found"})
+	LogError(log, "invalid", &app.Error{Op: "test", Code: app.EINVALID, Message: "invalid name"}, zap.String("foo", "bar"))
+	LogError(log, "internal", errors.New("db is down"))
+	Levels{app.ENOTFOUND: zap.DebugLevel}.LogError(log, "overridden", &app.Error{Op: "test", Code: app.ENOTFOUND, Message: "user not found"})
+	// Output: {"level":"info","msg":"not found","error":{"msg":"user not found","code":"not_found","trace":["test"]}}
+	// {"level":"warn","msg":"invalid","foo":"bar","error":{"msg":"invalid name","code":"invalid","trace":["test"]}}
+	// {"level":"error","msg":"internal","error":{"msg":"db is down"}}
+	// {"level":"debug","msg":"overridden","error":{"msg":"user not found","code":"not_found","trace":["test"]}}
+}
+
+func TestLevels_Level(t *testing.T) {
+	levels := Levels{app.ECONFLICT: zap.ErrorLevel}
+	unregistered := app.Code("levels_unregistered")
+
+	tests := []struct {
+		name string
+		err  error
+		want zapcore.Level
+	}{
+		{name: "nil", err: nil, want: zap.InfoLevel},
+		{name: "std error", err: errors.New("foo"), want: zap.ErrorLevel},
+		{name: "registered", err: app.NewError(app.EINVALID, "foo"), want: zap.WarnLevel},
+		{name: "overridden", err: app.NewError(app.ECONFLICT, "foo"), want: zap.ErrorLevel},
+		{name: "unregistered", err: app.NewError(unregistered, "foo"), want: zap.ErrorLevel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := levels.Level(tt.err); got != tt.want {
+				t.Fatalf("Level() want: %s, got: %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLogError_caller(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	log := zap.New(core, zap.AddCaller())
+
+	LogError(log, "test", errors.New("foo"))
+	Levels{}.LogError(log, "test", errors.New("foo"))
+
+	if logs.Len() != 2 {
+		t.Fatalf("logs want: 2, got: %d", logs.Len())
+	}
+	for _, entry := range logs.All() {
+		if !strings.HasSuffix(entry.Caller.File, "level_test.go") {
+			t.Fatalf("caller want level_test.go, got: %s", entry.Caller.File)
+		}
+	}
+}
+
+func TestLogError_metrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	log := zap.NewNop().WithOptions(zap.WrapCore(func(origin zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(origin, NewErrorMetricsCore(registry, "errors", "error"))
+	}))
+
+	LogError(log, "test", app.NewError(app.ENOTFOUND, "foo"))
+	LogError(log, "test", app.NewError(app.EINVALID, "foo"))
+	LogError(log, "test", app.NewError(app.EINTERNAL, "foo"))
+
+	const want = `
+# HELP errors Number of errors grouped by code
+# TYPE errors counter
+errors{code="internal"} 1
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(want), "errors"); err != nil {
+		t.Fatal(err)
+	}
+}
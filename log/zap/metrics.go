@@ -1,7 +1,11 @@
 package zap
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/MrEhbr/app"
+	"github.com/MrEhbr/app/internal/metrics"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"go.uber.org/zap"
@@ -13,16 +17,41 @@ var _ zapcore.Core = &metricCore{}
 type metricCore struct {
 	counter  *prometheus.CounterVec
 	errorKey string
+	labels   []Label
+	limits   []*metrics.LabelLimit
+	// fields added with With
+	fields []zapcore.Field
+	// key of the field with the trace id of exemplars, exemplars are disabled if empty
+	exemplarKey  string
+	fingerprints *metrics.FingerprintCounter
 }
 
 // NewErrorMetricsCore return core that will send metrics when log error
 func NewErrorMetricsCore(registry prometheus.Registerer, metricName, errorKey string) zapcore.Core {
+	return NewErrorMetricsCoreWithOptions(registry, metricName, errorKey)
+}
+
+// NewErrorMetricsCoreWithOptions return core that will send metrics when log error with labels set by the options,
+// the metric has only the code label by default.
+func NewErrorMetricsCoreWithOptions(registry prometheus.Registerer, metricName, errorKey string, opts ...MetricsOption) zapcore.Core {
+	o := newMetricsOptions(opts)
+	names := make([]string, 0, len(o.labels))
+	limits := make([]*metrics.LabelLimit, 0, len(o.labels))
+	for _, label := range o.labels {
+		names = append(names, label.Name)
+		limits = append(limits, metrics.NewLabelLimit(o.maxValues, o.allowed[label.Name]))
+	}
+
 	return &metricCore{
-		errorKey: errorKey,
+		errorKey:     errorKey,
+		labels:       o.labels,
+		limits:       limits,
+		exemplarKey:  o.exemplarKey,
+		fingerprints: metrics.NewFingerprintCounter(registry, o.fingerprintsMetric, o.fingerprintsLimit),
 		counter: promauto.With(registry).NewCounterVec(prometheus.CounterOpts{
 			Name: metricName,
-			Help: "Number of errors grouped by code",
-		}, []string{"code"}),
+			Help: "Number of errors grouped by " + strings.Join(names, ", "),
+		}, names),
 	}
 }
 
@@ -43,28 +72,64 @@ func (c *metricCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore
 }
 
 func (c *metricCore) With(fields []zapcore.Field) zapcore.Core {
-	return c
+	clone := *c
+	// copy the fields, so child cores don't share the backing array
+	clone.fields = make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	clone.fields = append(clone.fields, c.fields...)
+	clone.fields = append(clone.fields, fields...)
+
+	return &clone
 }
 
 func (c *metricCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
-	errField, ok := haveAppError(c.errorKey, fields)
-	if !ok {
-		c.counter.With(prometheus.Labels{"code": "none"}).Inc()
-		return nil
+	all := fields
+	if len(c.fields) > 0 {
+		all = make([]zapcore.Field, 0, len(c.fields)+len(fields))
+		all = append(all, c.fields...)
+		all = append(all, fields...)
 	}
 
-	errorCode := app.ErrorCode(errField.Interface.(error)).String()
-	if v, ok := errField.Interface.(interface{ ErrorCode() string }); ok {
-		errorCode = v.ErrorCode()
+	var err error
+	if errField, ok := haveAppError(c.errorKey, all); ok {
+		err = errField.Interface.(error)
 	}
 
-	c.counter.With(prometheus.Labels{"code": errorCode}).Inc()
+	values := make([]string, len(c.labels))
+	for i, label := range c.labels {
+		values[i] = c.limits[i].Value(label.Extract(entry, all, err))
+	}
+	metrics.AddWithExemplar(c.counter.WithLabelValues(values...), c.traceID(all))
+
+	if err != nil {
+		var op string
+		if trace := app.ErrorTrace(err); len(trace) > 0 {
+			op = trace[0]
+		}
+		c.fingerprints.Observe(app.ErrorCode(err).MetricLabel(), op)
+	}
 
 	return nil
 }
 
+// traceID returns the value of the exemplar field.
+func (c *metricCore) traceID(fields []zapcore.Field) string {
+	if c.exemplarKey == "" {
+		return ""
+	}
+
+	for i := len(fields) - 1; i >= 0; i-- {
+		if fields[i].Key == c.exemplarKey && fields[i].Type == zapcore.StringType {
+			return fields[i].String
+		}
+	}
+
+	return ""
+}
+
+// haveAppError returns the last field with the key, so entry fields take precedence over context fields.
 func haveAppError(key string, fields []zapcore.Field) (zapcore.Field, bool) {
-	for _, field := range fields {
+	for i := len(fields) - 1; i >= 0; i-- {
+		field := fields[i]
 		if field.Key == key {
 			_, ok := field.Interface.(error)
 			if !ok {
@@ -75,3 +140,90 @@ func haveAppError(key string, fields []zapcore.Field) (zapcore.Field, bool) {
 	}
 	return zapcore.Field{}, false
 }
+
+// Label extracts the value of the metric label from the entry, its context and entry fields and the logged error,
+// the error is nil if not logged.
+type Label struct {
+	Name    string
+	Extract func(entry zapcore.Entry, fields []zapcore.Field, err error) string
+}
+
+// CodeLabel returns the "code" label with the code of the error,
+// "unregistered" if the code is not registered and "none" if there is no error.
+func CodeLabel() Label {
+	return Label{Name: "code", Extract: func(_ zapcore.Entry, _ []zapcore.Field, err error) string {
+		if err == nil {
+			return metrics.NoneLabelValue
+		}
+		return app.ErrorCode(err).MetricLabel()
+	}}
+}
+
+// LoggerNameLabel returns the "logger" label with the name of the logger.
+func LoggerNameLabel() Label {
+	return Label{Name: "logger", Extract: func(entry zapcore.Entry, _ []zapcore.Field, _ error) string {
+		return metrics.OrNone(entry.LoggerName)
+	}}
+}
+
+// OpLabel returns the "op" label with the top-level Op of the error.
+func OpLabel() Label {
+	return Label{Name: "op", Extract: func(_ zapcore.Entry, _ []zapcore.Field, err error) string {
+		if trace := app.ErrorTrace(err); len(trace) > 0 {
+			return trace[0]
+		}
+		return metrics.NoneLabelValue
+	}}
+}
+
+// CallerPackageLabel returns the "package" label with the package of the caller,
+// the logger must be built with zap.AddCaller.
+func CallerPackageLabel() Label {
+	return Label{Name: "package", Extract: func(entry zapcore.Entry, _ []zapcore.Field, _ error) string {
+		if !entry.Caller.Defined {
+			return metrics.NoneLabelValue
+		}
+		return metrics.OrNone(callerPackage(entry.Caller.Function))
+	}}
+}
+
+// FieldLabel returns the label named after the key with the value of Error.Fields.
+func FieldLabel(key string) Label {
+	return Label{Name: key, Extract: func(_ zapcore.Entry, _ []zapcore.Field, err error) string {
+		v, ok := app.RedactedErrorFields(err)[key]
+		if !ok {
+			return metrics.NoneLabelValue
+		}
+		return metrics.OrNone(fmt.Sprint(v))
+	}}
+}
+
+// LogFieldLabel returns the label named after the key with the value of the logged field, e.g. added with zap.Logger.With.
+func LogFieldLabel(key string) Label {
+	return Label{Name: key, Extract: func(_ zapcore.Entry, fields []zapcore.Field, _ error) string {
+		for i := len(fields) - 1; i >= 0; i-- {
+			if fields[i].Key != key {
+				continue
+			}
+
+			if fields[i].Type == zapcore.StringType {
+				return metrics.OrNone(fields[i].String)
+			}
+			enc := zapcore.NewMapObjectEncoder()
+			fields[i].AddTo(enc)
+			return metrics.OrNone(fmt.Sprint(enc.Fields[key]))
+		}
+
+		return metrics.NoneLabelValue
+	}}
+}
+
+// callerPackage returns the package path of the function, e.g. "github.com/MrEhbr/app/log/zap.Error".
+func callerPackage(function string) string {
+	slash := strings.LastIndex(function, "/")
+	if dot := strings.Index(function[slash+1:], "."); dot >= 0 {
+		return function[:slash+1+dot]
+	}
+
+	return function
+}
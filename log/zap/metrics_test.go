@@ -3,6 +3,7 @@ package zap
 import (
 	"errors"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/MrEhbr/app"
@@ -28,6 +29,8 @@ func ExampleNewErrorMetricsCore() {
 	// {"level":"error","msg":"test","logger":"sublogger","error":{"msg":"test","code":"test_error_code","trace":["test"]}}
 }
 
+var registerCodeOnce sync.Once
+
 func Test_metricCore_Write(t *testing.T) {
 	const (
 		metricName = "errors"
@@ -58,6 +61,35 @@ func Test_metricCore_Write(t *testing.T) {
 			t.Fatal(err)
 		}
 	})
+	t.Run("custom code", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		log := zap.NewNop()
+		log = log.WithOptions(zap.WrapCore(func(origin zapcore.Core) zapcore.Core {
+			return zapcore.NewTee(origin, NewErrorMetricsCore(registry, "errors", "error"))
+		}),
+		)
+
+		const code app.Code = "zap_rate_limited"
+		// the registry is global, so the code is registered once with -count=N
+		registerCodeOnce.Do(func() {
+			app.MustRegisterCode(code, app.CodeInfo{Description: "Too many requests"})
+		})
+		log.Error("test", Error(&app.Error{Code: code}))
+		log.Error("test", Error(&app.Error{Code: "not_registered"}))
+		log.Error("test", zap.Error(errors.New("test")))
+
+		// The last \n at the end of this string is important
+		expected := strings.NewReader(`
+	# HELP errors Number of errors grouped by code
+	# TYPE errors counter
+	errors{code="internal"} 1
+	errors{code="unregistered"} 1
+	errors{code="zap_rate_limited"} 1
+	`)
+		if err := testutil.GatherAndCompare(registry, expected, "errors"); err != nil {
+			t.Fatal(err)
+		}
+	})
 	t.Run("invalid error field", func(t *testing.T) {
 		registry := prometheus.NewRegistry()
 		log := zap.NewNop()
@@ -78,6 +110,124 @@ func Test_metricCore_Write(t *testing.T) {
 			t.Fatal(err)
 		}
 	})
+	t.Run("labels", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		log := zap.NewNop().WithOptions(zap.AddCaller(), zap.WrapCore(func(origin zapcore.Core) zapcore.Core {
+			return zapcore.NewTee(origin, NewErrorMetricsCoreWithOptions(registry, metricName, errorKey,
+				WithLabels(CodeLabel(), LoggerNameLabel(), OpLabel(), CallerPackageLabel(), FieldLabel("tenant")),
+			))
+		}))
+
+		log.Named("users").Error("test", Error(app.OpError("users.Get", &app.Error{Op: "db.Query", Code: app.ENOTFOUND, Fields: map[string]interface{}{"tenant": "acme"}})))
+		log.Named("users").Error("test", Error(app.OpError("users.Get", &app.Error{Op: "db.Query", Code: app.ENOTFOUND, Fields: map[string]interface{}{"tenant": "acme"}})))
+		log.Error("test")
+
+		// The last \n at the end of this string is important
+		expected := strings.NewReader(`
+	# HELP errors Number of errors grouped by code, logger, op, package, tenant
+	# TYPE errors counter
+	errors{code="none",logger="none",op="none",package="github.com/MrEhbr/app/log/zap",tenant="none"} 1
+	errors{code="not_found",logger="users",op="users.Get",package="github.com/MrEhbr/app/log/zap",tenant="acme"} 2
+	`)
+		if err := testutil.GatherAndCompare(registry, expected, metricName); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("cardinality limit", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		log := zap.NewNop().WithOptions(zap.WrapCore(func(origin zapcore.Core) zapcore.Core {
+			return zapcore.NewTee(origin, NewErrorMetricsCoreWithOptions(registry, metricName, errorKey,
+				WithLabels(FieldLabel("tenant"), FieldLabel("endpoint")),
+				WithMaxLabelValues(2),
+				WithLabelValues("endpoint", "/users"),
+			))
+		}))
+
+		for _, fields := range []map[string]interface{}{
+			{"tenant": "a", "endpoint": "/users"},
+			{"tenant": "b", "endpoint": "/orders"},
+			{"tenant": "c", "endpoint": "/users"},
+			{"tenant": "a"},
+		} {
+			log.Error("test", Error(&app.Error{Code: app.ETEST, Fields: fields}))
+		}
+
+		// The last \n at the end of this string is important
+		expected := strings.NewReader(`
+	# HELP errors Number of errors grouped by tenant, endpoint
+	# TYPE errors counter
+	errors{endpoint="/users",tenant="a"} 1
+	errors{endpoint="/users",tenant="other"} 1
+	errors{endpoint="none",tenant="a"} 1
+	errors{endpoint="other",tenant="b"} 1
+	`)
+		if err := testutil.GatherAndCompare(registry, expected, metricName); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("with fields", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		log := zap.NewNop().WithOptions(zap.WrapCore(func(origin zapcore.Core) zapcore.Core {
+			return zapcore.NewTee(origin, NewErrorMetricsCoreWithOptions(registry, metricName, errorKey,
+				WithLabels(CodeLabel(), LogFieldLabel("endpoint")),
+			))
+		}))
+
+		parent := log.With(Error(&app.Error{Code: app.ECONFLICT}))
+		parent.Error("test")
+		parent.With(zap.String("endpoint", "/users")).Error("test")
+		parent.With(zap.String("endpoint", "/users")).With(zap.String("endpoint", "/orders")).Error("test")
+		parent.With(zap.String("endpoint", "/users")).Error("test", Error(&app.Error{Code: app.ETEST}))
+		log.With(zap.Int("endpoint", 1)).Error("test")
+
+		// The last \n at the end of this string is important
+		expected := strings.NewReader(`
+	# HELP errors Number of errors grouped by code, endpoint
+	# TYPE errors counter
+	errors{code="conflict",endpoint="/orders"} 1
+	errors{code="conflict",endpoint="/users"} 1
+	errors{code="conflict",endpoint="none"} 1
+	errors{code="none",endpoint="1"} 1
+	errors{code="test_error_code",endpoint="/users"} 1
+	`)
+		if err := testutil.GatherAndCompare(registry, expected, metricName); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("concurrent child loggers", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		log := zap.NewNop().WithOptions(zap.WrapCore(func(origin zapcore.Core) zapcore.Core {
+			return zapcore.NewTee(origin, NewErrorMetricsCoreWithOptions(registry, metricName, errorKey,
+				WithLabels(CodeLabel(), LogFieldLabel("worker")),
+			))
+		}))
+		parent := log.With(zap.String("service", "test"))
+
+		const workers = 8
+		var wg sync.WaitGroup
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func(worker int) {
+				defer wg.Done()
+				child := parent.With(zap.Int("worker", worker%2), Error(&app.Error{Code: app.ENOTFOUND}))
+				for j := 0; j < 10; j++ {
+					child.Error("test")
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		// The last \n at the end of this string is important
+		expected := strings.NewReader(`
+	# HELP errors Number of errors grouped by code, worker
+	# TYPE errors counter
+	errors{code="not_found",worker="0"} 40
+	errors{code="not_found",worker="1"} 40
+	`)
+		if err := testutil.GatherAndCompare(registry, expected, metricName); err != nil {
+			t.Fatal(err)
+		}
+	})
 	t.Run("don't send metric if level < Error", func(t *testing.T) {
 		registry := prometheus.NewRegistry()
 		log := zap.NewNop()
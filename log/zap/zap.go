@@ -16,19 +16,62 @@ func (e err) Error() string {
 	return e.origin.Error()
 }
 
-func (e err) ErrorCode() string {
-	return app.ErrorCode(e.origin).String()
+func (e err) Unwrap() error {
+	return e.origin
 }
 
 type errFields map[string]interface{}
 
 func (f errFields) MarshalLogObject(enc zapcore.ObjectEncoder) error {
 	for k, v := range f {
+		if violations, ok := v.([]app.Violation); ok {
+			if err := enc.AddArray(k, violationsArr(violations)); err != nil {
+				return err
+			}
+			continue
+		}
 		zap.Any(k, v).AddTo(enc)
 	}
 	return nil
 }
 
+type violationsArr []app.Violation
+
+func (a violationsArr) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, v := range a {
+		if err := enc.AppendObject(violation(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type violation app.Violation
+
+func (v violation) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("field", v.Field)
+	enc.AddString("rule", v.Rule)
+	if v.Value != nil {
+		zap.Any("value", v.Value).AddTo(enc)
+	}
+	enc.AddString("message", v.Message)
+	return nil
+}
+
+type errArr []error
+
+func (a errArr) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, e := range a {
+		if e == nil {
+			continue
+		}
+		if err := enc.AppendObject(err{origin: e}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 type stringsArr []string
 
 func (s stringsArr) MarshalLogArray(enc zapcore.ArrayEncoder) error {
@@ -45,15 +88,29 @@ func (e err) MarshalLogObject(enc zapcore.ObjectEncoder) error {
 	}
 
 	enc.AddString("msg", app.ErrorMessageDefault(e.origin, ""))
+	if detail := app.ErrorDetail(e.origin); detail != "" {
+		enc.AddString("detail", detail)
+	}
 	enc.AddString("code", app.ErrorCode(e.origin).String())
 	if err := enc.AddArray("trace", stringsArr(app.ErrorTrace(e.origin))); err != nil {
 		return err
 	}
-	if fields := app.ErrorFields(e.origin); len(fields) > 0 {
+	if fields := app.RedactedErrorFields(e.origin); len(fields) > 0 {
 		if err := enc.AddObject("fields", errFields(fields)); err != nil {
 			return err
 		}
 	}
+	var errs app.Errors
+	if errors.As(e.origin, &errs) {
+		if err := enc.AddArray("errors", errArr(errs)); err != nil {
+			return err
+		}
+	}
+	if stack := app.ErrorStack(e.origin); len(stack) > 0 {
+		if err := enc.AddArray("stack", stringsArr(stack.Strings())); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -68,3 +125,14 @@ func NamedError(key string, e error) zap.Field {
 func Error(err error) zap.Field {
 	return NamedError("error", err)
 }
+
+// FingerprintKey is the key of the fingerprint field.
+const FingerprintKey = "fingerprint"
+
+// Fingerprint returns the field of app.Fingerprint of the error, the field is skipped if the error is nil.
+func Fingerprint(err error, opts ...app.FingerprintOption) zap.Field {
+	if err == nil {
+		return zap.Skip()
+	}
+	return zap.String(FingerprintKey, app.Fingerprint(err, opts...))
+}
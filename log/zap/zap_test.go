@@ -1,10 +1,17 @@
 package zap
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"strings"
+	"testing"
 
 	"github.com/MrEhbr/app"
+	"github.com/google/go-cmp/cmp"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 func ExampleNew() {
@@ -14,8 +21,94 @@ func ExampleNew() {
 	log.Info("std error", Error(errors.New("foo")))
 	log.Info("wrapped std error", Error(app.ErrorWithCode(errors.New("foo"), app.ETEST)))
 	log.Info("error with fields", Error(&app.Error{Op: "test", Code: app.ETEST, Message: "foo", Fields: map[string]interface{}{"foo": "bar"}}))
+	log.Info("multiple errors", Error(app.OpError("batch", app.Join(
+		&app.Error{Op: "foo", Code: app.EINVALID, Message: "invalid", Fields: map[string]interface{}{"foo": "bar"}},
+		&app.Error{Op: "bar", Code: app.ENOTFOUND, Message: "not found"},
+	))))
+	log.Info("validation error", Error(app.NewValidation().Add("name", "required", "", "name is required").Err()))
+	log.Info("error with detail", Error(&app.Error{Op: "test", Code: app.ETEST, Message: "foo", Detail: "bar"}))
+	fpErr := &app.Error{Op: "test", Code: app.ETEST, Message: "foo"}
+	log.Info("error with fingerprint", Error(fpErr), Fingerprint(fpErr))
 	// Output: {"level":"info","msg":"nil error"}
 	// {"level":"info","msg":"std error","error":{"msg":"foo"}}
 	// {"level":"info","msg":"wrapped std error","error":{"msg":"foo","code":"test_error_code","trace":["github.com/MrEhbr/app/log/zap.ExampleNew"]}}
 	// {"level":"info","msg":"error with fields","error":{"msg":"foo","code":"test_error_code","trace":["test"],"fields":{"foo":"bar"}}}
+	// {"level":"info","msg":"multiple errors","error":{"msg":"not found","code":"not_found","trace":["batch","bar"],"fields":{"foo":"bar"},"errors":[{"msg":"invalid","code":"invalid","trace":["foo"],"fields":{"foo":"bar"}},{"msg":"not found","code":"not_found","trace":["bar"]}]}}
+	// {"level":"info","msg":"validation error","error":{"msg":"validation failed","code":"invalid","trace":["github.com/MrEhbr/app/log/zap.ExampleNew"],"fields":{"violations":[{"field":"name","rule":"required","value":"","message":"name is required"}]}}}
+	// {"level":"info","msg":"error with detail","error":{"msg":"foo","detail":"bar","code":"test_error_code","trace":["test"]}}
+	// {"level":"info","msg":"error with fingerprint","error":{"msg":"foo","code":"test_error_code","trace":["test"]},"fingerprint":"d163a4bbf1713acc"}
+}
+
+func TestError_stack(t *testing.T) {
+	app.EnableStackCapture(true)
+	defer app.EnableStackCapture(false)
+
+	var buf bytes.Buffer
+	log := zap.New(zapcore.NewCore(zapcore.NewJSONEncoder(zapcore.EncoderConfig{MessageKey: "msg"}), zapcore.AddSync(&buf), zap.DebugLevel))
+	log.Info("test", Error(app.ErrorWithCode(errors.New("foo"), app.ETEST)))
+
+	var entry struct {
+		Error struct {
+			Stack []string `json:"stack"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entry.Error.Stack) == 0 {
+		t.Fatalf("stack is not logged: %s", buf.String())
+	}
+
+	const want = "github.com/MrEhbr/app/log/zap.TestError_stack "
+	if !strings.HasPrefix(entry.Error.Stack[0], want) {
+		t.Fatalf("first frame want prefix: %s, got: %s", want, entry.Error.Stack[0])
+	}
+}
+
+func TestError_contextFields(t *testing.T) {
+	var buf bytes.Buffer
+	log := zap.New(zapcore.NewCore(zapcore.NewJSONEncoder(zapcore.EncoderConfig{MessageKey: "msg"}), zapcore.AddSync(&buf), zap.DebugLevel))
+
+	ctx := app.WithFields(context.Background(), map[string]interface{}{"request_id": "1", "tenant": "acme"})
+	log.Info("test", Error(app.OpErrorCtx(ctx, "test", &app.Error{Code: app.ETEST, Fields: map[string]interface{}{"tenant": "error"}})))
+
+	var entry struct {
+		Error struct {
+			Fields map[string]interface{} `json:"fields"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]interface{}{"request_id": "1", "tenant": "error"}
+	if diff := cmp.Diff(want, entry.Error.Fields); diff != "" {
+		t.Fatalf("fields mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestError_sensitiveFields(t *testing.T) {
+	var buf bytes.Buffer
+	log := zap.New(zapcore.NewCore(zapcore.NewJSONEncoder(zapcore.EncoderConfig{MessageKey: "msg"}), zapcore.AddSync(&buf), zap.DebugLevel))
+
+	log.Info("test", Error(&app.Error{Code: app.ETEST, Fields: map[string]interface{}{
+		"id":      1,
+		"token":   "abc",
+		"address": app.Sensitive("Baker Street"),
+	}}))
+
+	var entry struct {
+		Error struct {
+			Fields map[string]interface{} `json:"fields"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]interface{}{"id": float64(1), "token": app.RedactedMask, "address": app.RedactedMask}
+	if diff := cmp.Diff(want, entry.Error.Fields); diff != "" {
+		t.Fatalf("fields mismatch (-want +got):\n%s", diff)
+	}
 }
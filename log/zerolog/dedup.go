@@ -0,0 +1,190 @@
+package zerolog
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MrEhbr/app/internal/dedup"
+	"github.com/rs/zerolog"
+	"github.com/valyala/fastjson"
+)
+
+const (
+	// SuppressedKey is the key of the number of suppressed events in the summary event.
+	SuppressedKey = "suppressed"
+
+	// DefaultDedupWindow is the default window of deduplication.
+	DefaultDedupWindow = dedup.DefaultWindow
+	// DefaultDedupFirst is the default number of events written per fingerprint within the window.
+	DefaultDedupFirst = dedup.DefaultFirst
+)
+
+// DedupOption configures the deduplication.
+type DedupOption func(*dedup.Options)
+
+// WithDedupWindow sets the window of deduplication.
+func WithDedupWindow(window time.Duration) DedupOption {
+	return func(o *dedup.Options) {
+		o.Window = window
+	}
+}
+
+// WithDedupFirst sets the number of events written per fingerprint within the window.
+func WithDedupFirst(n int) DedupOption {
+	return func(o *dedup.Options) {
+		o.First = n
+	}
+}
+
+// WithDedupClock sets the clock, e.g. for tests.
+func WithDedupClock(now func() time.Time) DedupOption {
+	return func(o *dedup.Options) {
+		o.Now = now
+	}
+}
+
+// dedupEvent is the suppressed event.
+type dedupEvent struct {
+	level zerolog.Level
+	event []byte
+}
+
+type dedupWriter struct {
+	w         zerolog.LevelWriter
+	window    *dedup.Window[dedupEvent]
+	parsers   *fastjson.ParserPool
+	codePath  []string
+	tracePath []string
+}
+
+// NewDedupWriter returns writer that deduplicates events with errors logged with ErrorMarshaler under the dot-separated path.
+// The fingerprint is the FingerprintKey field added with Fingerprint, so events are grouped by app.Fingerprint
+// the same way as NewDedupCore of zap does, otherwise the code plus the trace of the error.
+// Events without the fingerprint and the error code are written as is.
+// Within the window, the first events of each fingerprint are written to the w and the rest are suppressed.
+// When the window is over, the last suppressed event is written with the SuppressedKey field
+// holding the number of suppressed events. Summaries are written by following events or on Flush.
+// The writer doesn't start goroutines, so call Flush periodically, e.g. by time.Ticker, and on shutdown
+// to not lose summaries when no events follow.
+//
+// It's a writer rather than zerolog.Hook, because hooks don't see fields of the event, so they can't read the fingerprint.
+//
+// Combine the writer with NewErrorMetricsWriter in zerolog.MultiLevelWriter to count every error, including suppressed ones.
+func NewDedupWriter(w io.Writer, errorPath string, opts ...DedupOption) *dedupWriter {
+	lw, ok := w.(zerolog.LevelWriter)
+	if !ok {
+		lw = levelWriter{w}
+	}
+
+	return &dedupWriter{
+		w:         lw,
+		window:    dedup.NewWindow(dedup.NewOptions(opts), cloneDedupEvent),
+		parsers:   &fastjson.ParserPool{},
+		codePath:  append(splitPath(errorPath), "code"),
+		tracePath: append(splitPath(errorPath), "trace"),
+	}
+}
+
+func (w *dedupWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (w *dedupWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	fingerprint, ok := w.fingerprint(p)
+	if !ok {
+		return w.w.WriteLevel(level, p)
+	}
+
+	write, summaries := w.window.Observe(fingerprint, dedupEvent{level: level, event: p})
+	if err := w.writeSummaries(summaries); err != nil {
+		return 0, err
+	}
+	if !write {
+		return len(p), nil
+	}
+
+	return w.w.WriteLevel(level, p)
+}
+
+// Flush writes summaries of suppressed events and resets their suppressed count, the windows go on.
+func (w *dedupWriter) Flush() error {
+	return w.writeSummaries(w.window.Flush())
+}
+
+// fingerprint returns the FingerprintKey field of the event, or the code plus the trace of the error
+// if the event has no such field, false if the event has neither the field nor the error code.
+func (w *dedupWriter) fingerprint(p []byte) (string, bool) {
+	parser := w.parsers.Get()
+	defer w.parsers.Put(parser)
+
+	v, err := parser.ParseBytes(p)
+	if err != nil {
+		return "", false
+	}
+
+	if fingerprint := v.GetStringBytes(FingerprintKey); len(fingerprint) > 0 {
+		return string(fingerprint), true
+	}
+
+	code := v.GetStringBytes(w.codePath...)
+	if len(code) == 0 {
+		return "", false
+	}
+
+	// zero bytes don't occur in fingerprints, so the keys don't collide
+	var buf strings.Builder
+	buf.Write(code)
+	for _, op := range v.GetArray(w.tracePath...) {
+		buf.WriteByte(0)
+		buf.Write(op.GetStringBytes())
+	}
+
+	return buf.String(), true
+}
+
+// cloneDedupEvent copies the event, because zerolog reuses the buffer of the event.
+func cloneDedupEvent(e dedupEvent) dedupEvent {
+	e.event = append([]byte(nil), e.event...)
+	return e
+}
+
+func (w *dedupWriter) writeSummaries(summaries []dedup.Summary[dedupEvent]) error {
+	for _, s := range summaries {
+		if _, err := w.w.WriteLevel(s.Entry.level, summary(s.Entry.event, s.Suppressed)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// summary returns the event with the SuppressedKey field added.
+func summary(event []byte, suppressed int) []byte {
+	event = bytes.TrimRight(event, "\n")
+	end := bytes.LastIndexByte(event, '}')
+	if end < 0 {
+		return event
+	}
+
+	res := make([]byte, 0, len(event)+len(SuppressedKey)+16)
+	res = append(res, event[:end]...)
+	if end > 1 {
+		res = append(res, ',')
+	}
+	res = append(res, `"`+SuppressedKey+`":`...)
+	res = strconv.AppendInt(res, int64(suppressed), 10)
+	res = append(res, event[end:]...)
+
+	return append(res, '\n')
+}
+
+type levelWriter struct {
+	io.Writer
+}
+
+func (w levelWriter) WriteLevel(_ zerolog.Level, p []byte) (int, error) {
+	return w.Write(p)
+}
@@ -0,0 +1,144 @@
+package zerolog
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MrEhbr/app"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rs/zerolog"
+)
+
+func ExampleNewDedupWriter() {
+	originalErrorMarshalFunc := zerolog.ErrorMarshalFunc
+	defer func() {
+		zerolog.ErrorMarshalFunc = originalErrorMarshalFunc
+	}()
+
+	zerolog.ErrorMarshalFunc = ErrorMarshaler
+
+	now := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	log := zerolog.New(NewDedupWriter(os.Stdout, "error", WithDedupFirst(2), WithDedupWindow(time.Minute), WithDedupClock(func() time.Time { return now })))
+
+	err := &app.Error{Op: "query", Code: app.EINTERNAL, Message: "db is down"}
+	for i := 0; i < 5; i++ {
+		log.Error().Err(err).Func(Fingerprint(err)).Msg("query failed")
+	}
+	log.Error().Err(errors.New("foo")).Msg("std error")
+	other := &app.Error{Op: "publish", Code: app.EINTERNAL, Message: "broker is down"}
+	log.Error().Err(other).Func(Fingerprint(other)).Msg("other error")
+
+	now = now.Add(time.Minute)
+	log.Error().Err(err).Func(Fingerprint(err)).Msg("query failed")
+	// Output: {"level":"error","error":{"msg":"db is down","code":"internal","trace":["query"]},"fingerprint":"bb17dfa0a6700149","message":"query failed"}
+	// {"level":"error","error":{"msg":"db is down","code":"internal","trace":["query"]},"fingerprint":"bb17dfa0a6700149","message":"query failed"}
+	// {"level":"error","error":{"msg":"foo"},"message":"std error"}
+	// {"level":"error","error":{"msg":"broker is down","code":"internal","trace":["publish"]},"fingerprint":"f68d098e1aa1b272","message":"other error"}
+	// {"level":"error","error":{"msg":"db is down","code":"internal","trace":["query"]},"fingerprint":"bb17dfa0a6700149","message":"query failed","suppressed":3}
+	// {"level":"error","error":{"msg":"db is down","code":"internal","trace":["query"]},"fingerprint":"bb17dfa0a6700149","message":"query failed"}
+}
+
+func TestDedupWriter(t *testing.T) {
+	originalErrorMarshalFunc := zerolog.ErrorMarshalFunc
+	defer func() {
+		zerolog.ErrorMarshalFunc = originalErrorMarshalFunc
+	}()
+
+	zerolog.ErrorMarshalFunc = ErrorMarshaler
+
+	now := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	t.Run("metrics count suppressed", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		var buf bytes.Buffer
+		log := zerolog.New(zerolog.MultiLevelWriter(
+			NewDedupWriter(&buf, "error", WithDedupFirst(1), WithDedupClock(clock)),
+			NewErrorMetricsWriter(registry, "error.code", "errors"),
+		))
+
+		for i := 0; i < 5; i++ {
+			err := app.NewError(app.EINTERNAL, "foo")
+			log.Error().Err(err).Func(Fingerprint(err)).Send()
+		}
+
+		if got := strings.Count(buf.String(), "\n"); got != 1 {
+			t.Fatalf("logs want: 1, got: %d", got)
+		}
+
+		const want = `
+# HELP errors Number of errors grouped by code
+# TYPE errors counter
+errors{code="internal"} 5
+`
+		if err := testutil.GatherAndCompare(registry, strings.NewReader(want), "errors"); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("fingerprint field", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := zerolog.New(NewDedupWriter(&buf, "error", WithDedupFirst(1), WithDedupClock(clock)))
+
+		// the same code and trace, but different group keys
+		for _, key := range []string{"foo", "bar", "foo"} {
+			err := &app.Error{Op: "test", Code: app.EINTERNAL, GroupKey: key}
+			log.Error().Err(err).Func(Fingerprint(err)).Send()
+		}
+
+		if got := strings.Count(buf.String(), "\n"); got != 2 {
+			t.Fatalf("logs want: 2, got: %d", got)
+		}
+	})
+
+	t.Run("code and trace without fingerprint", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := zerolog.New(NewDedupWriter(&buf, "error", WithDedupFirst(1), WithDedupClock(clock)))
+
+		for i := 0; i < 3; i++ {
+			log.Error().Err(&app.Error{Op: "query", Code: app.EINTERNAL, Message: "db is down"}).Msg("query failed")
+			log.Error().Err(&app.Error{Op: "publish", Code: app.EINTERNAL}).Msg("publish failed")
+			log.Error().Err(errors.New("foo")).Msg("std error")
+		}
+
+		const want = `{"level":"error","error":{"msg":"db is down","code":"internal","trace":["query"]},"message":"query failed"}
+{"level":"error","error":{"msg":"publish: <internal>","code":"internal","trace":["publish"]},"message":"publish failed"}
+{"level":"error","error":{"msg":"foo"},"message":"std error"}
+{"level":"error","error":{"msg":"foo"},"message":"std error"}
+{"level":"error","error":{"msg":"foo"},"message":"std error"}
+`
+		if got := buf.String(); got != want {
+			t.Fatalf("logs want:\n%s\ngot:\n%s", want, got)
+		}
+	})
+
+	t.Run("flush writes summaries", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := NewDedupWriter(&buf, "error", WithDedupFirst(1), WithDedupClock(clock))
+		log := zerolog.New(w)
+		err := &app.Error{Op: "test", Code: app.EINTERNAL}
+
+		for i := 0; i < 3; i++ {
+			log.Error().Err(err).Func(Fingerprint(err)).Int("i", i).Send()
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		log.Error().Err(err).Func(Fingerprint(err)).Send()
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("logs want: 2, got: %s", buf.String())
+		}
+
+		const want = `{"level":"error","error":{"msg":"test: <internal>","code":"internal","trace":["test"]},"fingerprint":"575e8527b829c7ba","i":2,"suppressed":2}`
+		if lines[1] != want {
+			t.Fatalf("summary want:\n%s\ngot:\n%s", want, lines[1])
+		}
+	})
+}
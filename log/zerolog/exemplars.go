@@ -0,0 +1,36 @@
+package zerolog
+
+import "github.com/MrEhbr/app/internal/metrics"
+
+const (
+	// ExemplarLabel is the label of exemplars with the trace id.
+	ExemplarLabel = metrics.ExemplarLabel
+
+	// DefaultFingerprintsLimit is the default number of fingerprints remembered by WithFingerprints.
+	DefaultFingerprintsLimit = metrics.DefaultFingerprintsLimit
+)
+
+// WithExemplars attaches exemplars with the trace id read from the dot-separated path to each increment of the metric.
+func WithExemplars(path string) MetricsOption {
+	return func(o *metricsOptions) {
+		o.exemplarPath = path
+	}
+}
+
+// WithFingerprints enables the counter with the metric name incremented once per new error fingerprint,
+// the fingerprint is the code plus the top-level Op of the error logged with ErrorMarshaler under the dot-separated path.
+func WithFingerprints(metricName, errorPath string) MetricsOption {
+	return func(o *metricsOptions) {
+		o.fingerprintsMetric = metricName
+		o.fingerprintsErrorPath = errorPath
+	}
+}
+
+// WithFingerprintsLimit sets the number of fingerprints remembered by WithFingerprints,
+// the least recently seen ones are forgotten and counted again when seen next time.
+// Not positive n means no limit.
+func WithFingerprintsLimit(n int) MetricsOption {
+	return func(o *metricsOptions) {
+		o.fingerprintsLimit = n
+	}
+}
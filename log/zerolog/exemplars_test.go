@@ -0,0 +1,110 @@
+package zerolog
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/MrEhbr/app"
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+	"github.com/rs/zerolog"
+)
+
+// gatherOpenMetrics returns metrics of the registry in the OpenMetrics text format without exemplar timestamps.
+func gatherOpenMetrics(t *testing.T, registry *prometheus.Registry) string {
+	t.Helper()
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	for _, family := range families {
+		for _, metric := range family.Metric {
+			if metric.Counter != nil && metric.Counter.Exemplar != nil {
+				metric.Counter.Exemplar.Timestamp = nil
+			}
+		}
+		if _, err := expfmt.MetricFamilyToOpenMetrics(&buf, family); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := expfmt.FinalizeOpenMetrics(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.String()
+}
+
+func Test_metricsWriter_exemplars(t *testing.T) {
+	originalErrorMarshalFunc := zerolog.ErrorMarshalFunc
+	defer func() {
+		zerolog.ErrorMarshalFunc = originalErrorMarshalFunc
+	}()
+
+	zerolog.ErrorMarshalFunc = ErrorMarshaler
+
+	registry := prometheus.NewRegistry()
+	metricsWriter := NewErrorMetricsWriterWithOptions(registry, "errors_total",
+		WithExemplars("trace_id"),
+		WithFingerprints("error_fingerprints_total", "error"),
+	)
+	log := zerolog.New(zerolog.MultiLevelWriter(metricsWriter, io.Discard))
+
+	log.Error().Err(app.OpError("users.Get", &app.Error{Code: app.ENOTFOUND})).Str("trace_id", "abc").Msg("test")
+	sublogger := log.With().Str("trace_id", "def").Logger()
+	sublogger.Error().Err(app.OpError("users.Get", &app.Error{Code: app.ENOTFOUND})).Msg("test")
+	log.Error().Err(app.OpError("users.List", &app.Error{Code: app.ENOTFOUND})).Msg("test")
+	log.Error().Err(&app.Error{Op: "users.Get", Code: app.ETEST}).Str("trace_id", "ghi").Msg("test")
+	log.Error().Msg("test")
+
+	want := `# HELP error_fingerprints Number of first-seen error fingerprints grouped by code
+# TYPE error_fingerprints counter
+error_fingerprints_total{code="not_found"} 2.0
+error_fingerprints_total{code="test_error_code"} 1.0
+# HELP errors Number of errors grouped by code
+# TYPE errors counter
+errors_total{code="none"} 1.0
+errors_total{code="not_found"} 3.0 # {trace_id="def"} 1.0
+errors_total{code="test_error_code"} 1.0 # {trace_id="ghi"} 1.0
+# EOF
+`
+	if diff := cmp.Diff(want, gatherOpenMetrics(t, registry)); diff != "" {
+		t.Fatalf("metrics mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_metricsWriter_fingerprintsLimit(t *testing.T) {
+	originalErrorMarshalFunc := zerolog.ErrorMarshalFunc
+	defer func() {
+		zerolog.ErrorMarshalFunc = originalErrorMarshalFunc
+	}()
+
+	zerolog.ErrorMarshalFunc = ErrorMarshaler
+
+	registry := prometheus.NewRegistry()
+	log := zerolog.New(NewErrorMetricsWriterWithOptions(registry, "errors_total",
+		WithFingerprints("error_fingerprints_total", "error"),
+		WithFingerprintsLimit(1),
+	))
+
+	// users.Get is forgotten when users.List is seen, so it's counted again
+	for _, op := range []string{"users.Get", "users.Get", "users.List", "users.Get"} {
+		log.Error().Err(app.OpError(op, &app.Error{Code: app.ENOTFOUND})).Msg("test")
+	}
+
+	want := `# HELP error_fingerprints Number of first-seen error fingerprints grouped by code
+# TYPE error_fingerprints counter
+error_fingerprints_total{code="not_found"} 3.0
+# HELP errors Number of errors grouped by code
+# TYPE errors counter
+errors_total{code="not_found"} 4.0
+# EOF
+`
+	if diff := cmp.Diff(want, gatherOpenMetrics(t, registry)); diff != "" {
+		t.Fatalf("metrics mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,55 @@
+package zerolog
+
+import (
+	"github.com/MrEhbr/app"
+	"github.com/MrEhbr/app/internal/mapx"
+	"github.com/rs/zerolog"
+)
+
+// Levels overrides log levels of error codes from app.CodeInfo.
+type Levels map[app.Code]zerolog.Level
+
+// With returns a copy of l extended with overrides.
+func (l Levels) With(overrides Levels) Levels {
+	return mapx.Merge(l, overrides)
+}
+
+// Level returns the log level of the err.
+// If err is nil returns zerolog.InfoLevel,
+// if the code is neither in l nor registered with LogLevel returns zerolog.ErrorLevel.
+func (l Levels) Level(err error) zerolog.Level {
+	if err == nil {
+		return zerolog.InfoLevel
+	}
+
+	code := app.ErrorCode(err)
+	if level, ok := l[code]; ok {
+		return level
+	}
+
+	if info, ok := app.LookupCode(code); ok && info.LogLevel != "" {
+		if level, err := zerolog.ParseLevel(info.LogLevel); err == nil {
+			return level
+		}
+	}
+
+	return zerolog.ErrorLevel
+}
+
+// Event starts a new message with the err at the level of the err, e.g.
+//
+//	levels.Event(&log, err).Str("user", id).Msg("get user")
+func (l Levels) Event(logger *zerolog.Logger, err error) *zerolog.Event {
+	return logger.WithLevel(l.Level(err)).Err(err)
+}
+
+// LogError logs the err with the msg at the level of the err,
+// so client errors like app.ENOTFOUND aren't counted by NewErrorMetricsWriter.
+func (l Levels) LogError(logger *zerolog.Logger, msg string, err error) {
+	l.Event(logger, err).CallerSkipFrame(1).Msg(msg)
+}
+
+// LogError logs the err with the msg at the level of the err using the zero Levels.
+func LogError(logger *zerolog.Logger, msg string, err error) {
+	Levels{}.Event(logger, err).CallerSkipFrame(1).Msg(msg)
+}
@@ -0,0 +1,103 @@
+package zerolog
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/MrEhbr/app"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rs/zerolog"
+)
+
+func ExampleLogError() {
+	originalErrorMarshalFunc := zerolog.ErrorMarshalFunc
+	defer func() {
+		zerolog.ErrorMarshalFunc = originalErrorMarshalFunc
+	}()
+
+	zerolog.ErrorMarshalFunc = ErrorMarshaler
+
+	log := zerolog.New(os.Stdout)
+
+	LogError(&log, "not found", &app.Error{Op: "test", Code: app.ENOTFOUND, Message: "user not found"})
+	LogError(&log, "internal", errors.New("db is down"))
+	levels := Levels{app.ENOTFOUND: zerolog.DebugLevel}
+	levels.LogError(&log, "overridden", &app.Error{Op: "test", Code: app.ENOTFOUND, Message: "user not found"})
+	levels.Event(&log, &app.Error{Op: "test", Code: app.EINVALID, Message: "invalid name"}).Str("foo", "bar").Msg("event")
+	// Output: {"level":"info","error":{"msg":"user not found","code":"not_found","trace":["test"]},"message":"not found"}
+	// {"level":"error","error":{"msg":"db is down"},"message":"internal"}
+	// {"level":"debug","error":{"msg":"user not found","code":"not_found","trace":["test"]},"message":"overridden"}
+	// {"level":"warn","error":{"msg":"invalid name","code":"invalid","trace":["test"]},"foo":"bar","message":"event"}
+}
+
+func TestLevels_Level(t *testing.T) {
+	levels := Levels{app.ECONFLICT: zerolog.ErrorLevel}
+	unregistered := app.Code("levels_unregistered")
+
+	tests := []struct {
+		name string
+		err  error
+		want zerolog.Level
+	}{
+		{name: "nil", err: nil, want: zerolog.InfoLevel},
+		{name: "std error", err: errors.New("foo"), want: zerolog.ErrorLevel},
+		{name: "registered", err: app.NewError(app.EINVALID, "foo"), want: zerolog.WarnLevel},
+		{name: "overridden", err: app.NewError(app.ECONFLICT, "foo"), want: zerolog.ErrorLevel},
+		{name: "unregistered", err: app.NewError(unregistered, "foo"), want: zerolog.ErrorLevel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := levels.Level(tt.err); got != tt.want {
+				t.Fatalf("Level() want: %s, got: %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLogError_caller(t *testing.T) {
+	var buf bytes.Buffer
+	log := zerolog.New(&buf).With().Caller().Logger()
+
+	LogError(&log, "test", errors.New("foo"))
+	Levels{}.LogError(&log, "test", errors.New("foo"))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("logs want: 2, got: %s", buf.String())
+	}
+	for _, line := range lines {
+		if !strings.Contains(line, "level_test.go:") {
+			t.Fatalf("caller want level_test.go, got: %s", line)
+		}
+	}
+}
+
+func TestLogError_metrics(t *testing.T) {
+	originalErrorMarshalFunc := zerolog.ErrorMarshalFunc
+	defer func() {
+		zerolog.ErrorMarshalFunc = originalErrorMarshalFunc
+	}()
+
+	zerolog.ErrorMarshalFunc = ErrorMarshaler
+
+	registry := prometheus.NewRegistry()
+	log := zerolog.New(NewErrorMetricsWriter(registry, "error.code", "errors"))
+
+	LogError(&log, "test", app.NewError(app.ENOTFOUND, "foo"))
+	LogError(&log, "test", app.NewError(app.EINVALID, "foo"))
+	LogError(&log, "test", app.NewError(app.EINTERNAL, "foo"))
+
+	const want = `
+# HELP errors Number of errors grouped by code
+# TYPE errors counter
+errors{code="internal"} 1
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(want), "errors"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,8 +1,11 @@
 package zerolog
 
 import (
+	"path"
 	"strings"
 
+	"github.com/MrEhbr/app"
+	"github.com/MrEhbr/app/internal/metrics"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/rs/zerolog"
@@ -10,17 +13,45 @@ import (
 )
 
 type metricsWriter struct {
-	counter  *prometheus.CounterVec
-	codePath []string
+	counter *prometheus.CounterVec
+	labels  []Label
+	limits  []*metrics.LabelLimit
+	parsers *fastjson.ParserPool
+	// path of the trace id of exemplars, exemplars are disabled if empty
+	exemplarPath []string
+	// paths of the code and the top-level Op of fingerprints
+	codePath     []string
+	opPath       []string
+	fingerprints *metrics.FingerprintCounter
 }
 
 func NewErrorMetricsWriter(registry prometheus.Registerer, codePath, metricName string) *metricsWriter {
+	return NewErrorMetricsWriterWithOptions(registry, metricName, WithLabels(CodeLabel(codePath)))
+}
+
+// NewErrorMetricsWriterWithOptions returns writer that will send metrics when log error with labels set by the options,
+// the metric has only the code label read from "error.code" by default.
+func NewErrorMetricsWriterWithOptions(registry prometheus.Registerer, metricName string, opts ...MetricsOption) *metricsWriter {
+	o := newMetricsOptions(opts)
+	names := make([]string, 0, len(o.labels))
+	limits := make([]*metrics.LabelLimit, 0, len(o.labels))
+	for _, label := range o.labels {
+		names = append(names, label.Name)
+		limits = append(limits, metrics.NewLabelLimit(o.maxValues, o.allowed[label.Name]))
+	}
+
 	return &metricsWriter{
-		codePath: strings.Split(codePath, "."),
+		labels:       o.labels,
+		limits:       limits,
+		parsers:      &fastjson.ParserPool{},
+		exemplarPath: splitPath(o.exemplarPath),
+		codePath:     append(splitPath(o.fingerprintsErrorPath), "code"),
+		opPath:       append(splitPath(o.fingerprintsErrorPath), "trace", "0"),
+		fingerprints: metrics.NewFingerprintCounter(registry, o.fingerprintsMetric, o.fingerprintsLimit),
 		counter: promauto.With(registry).NewCounterVec(prometheus.CounterOpts{
 			Name: metricName,
-			Help: "Number of errors grouped by code",
-		}, []string{"code"}),
+			Help: "Number of errors grouped by " + strings.Join(names, ", "),
+		}, names),
 	}
 }
 
@@ -33,11 +64,103 @@ func (w metricsWriter) WriteLevel(level zerolog.Level, p []byte) (n int, err err
 }
 
 func (w metricsWriter) Write(p []byte) (n int, err error) {
-	code := fastjson.GetString(p, w.codePath...)
-	if code == "" {
-		code = "none"
+	parser := w.parsers.Get()
+	defer w.parsers.Put(parser)
+
+	// invalid events are counted with "none" labels
+	v, _ := parser.ParseBytes(p)
+
+	values := make([]string, len(w.labels))
+	for i, label := range w.labels {
+		values[i] = w.limits[i].Value(label.Extract(v))
+	}
+	var traceID string
+	if len(w.exemplarPath) > 0 {
+		traceID = string(v.GetStringBytes(w.exemplarPath...))
+	}
+	metrics.AddWithExemplar(w.counter.WithLabelValues(values...), traceID)
+
+	if w.fingerprints != nil {
+		if code := string(v.GetStringBytes(w.codePath...)); code != "" {
+			w.fingerprints.Observe(app.Code(code).MetricLabel(), string(v.GetStringBytes(w.opPath...)))
+		}
 	}
 
-	w.counter.With(prometheus.Labels{"code": code}).Inc()
 	return len(p), nil
 }
+
+func splitPath(path string) []string {
+	if path == "" {
+		return nil
+	}
+
+	return strings.Split(path, ".")
+}
+
+// Label extracts the value of the metric label from the parsed event, the event is nil if it's not valid JSON.
+type Label struct {
+	Name    string
+	Extract func(event *fastjson.Value) string
+}
+
+// CodeLabel returns the "code" label with the code read from the dot-separated path,
+// "unregistered" if the code is not registered and "none" if there is no code.
+func CodeLabel(codePath string) Label {
+	keys := strings.Split(codePath, ".")
+	return Label{Name: "code", Extract: func(event *fastjson.Value) string {
+		code := string(event.GetStringBytes(keys...))
+		if code == "" {
+			return metrics.NoneLabelValue
+		}
+
+		return app.Code(code).MetricLabel()
+	}}
+}
+
+// LoggerNameLabel returns the "logger" label with the logger name read from the key, e.g. set with zerolog.Context.Str.
+func LoggerNameLabel(key string) Label {
+	return Label{Name: "logger", Extract: func(event *fastjson.Value) string {
+		return metrics.OrNone(string(event.GetStringBytes(key)))
+	}}
+}
+
+// OpLabel returns the "op" label with the top-level Op of the error logged with ErrorMarshaler under the dot-separated path.
+func OpLabel(errorPath string) Label {
+	keys := append(strings.Split(errorPath, "."), "trace", "0")
+	return Label{Name: "op", Extract: func(event *fastjson.Value) string {
+		return metrics.OrNone(string(event.GetStringBytes(keys...)))
+	}}
+}
+
+// CallerPackageLabel returns the "package" label with the directory name of the caller file,
+// the logger must be built with zerolog.Context.Caller.
+func CallerPackageLabel() Label {
+	return Label{Name: "package", Extract: func(event *fastjson.Value) string {
+		caller := string(event.GetStringBytes(zerolog.CallerFieldName))
+		if caller == "" {
+			return metrics.NoneLabelValue
+		}
+		if i := strings.LastIndex(caller, ":"); i >= 0 {
+			caller = caller[:i]
+		}
+
+		return path.Base(path.Dir(caller))
+	}}
+}
+
+// FieldLabel returns the label named after the key with the value of Error.Fields
+// of the error logged with ErrorMarshaler under the dot-separated path.
+func FieldLabel(errorPath, key string) Label {
+	keys := append(strings.Split(errorPath, "."), "fields", key)
+	return Label{Name: key, Extract: func(event *fastjson.Value) string {
+		v := event.Get(keys...)
+		if v == nil {
+			return metrics.NoneLabelValue
+		}
+		if v.Type() == fastjson.TypeString {
+			return metrics.OrNone(string(v.GetStringBytes()))
+		}
+
+		return v.String()
+	}}
+}
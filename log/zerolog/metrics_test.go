@@ -5,6 +5,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/MrEhbr/app"
@@ -35,6 +36,8 @@ func ExampleNewErrorMetricsWriter() {
 	// {"level":"error","error":{"msg":"foo","code":"test_error_code","trace":["github.com/MrEhbr/app/log/zerolog.ExampleNewErrorMetricsWriter"]},"message":"wrapped std error"}
 }
 
+var registerCodeOnce sync.Once
+
 func Test_metricsWriter_Write(t *testing.T) {
 	const (
 		metricName = "errors"
@@ -91,6 +94,91 @@ errors{code="none"} 1
 		}
 	})
 
+	t.Run("custom code", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		metricsWriter := NewErrorMetricsWriter(registry, "error.code", "errors")
+		multi := zerolog.MultiLevelWriter(metricsWriter, io.Discard)
+
+		log := zerolog.New(multi)
+
+		const code app.Code = "zerolog_rate_limited"
+		// the registry is global, so the code is registered once with -count=N
+		registerCodeOnce.Do(func() {
+			app.MustRegisterCode(code, app.CodeInfo{Description: "Too many requests"})
+		})
+		log.Error().Err(&app.Error{Code: code}).Msg("test")
+		log.Error().Err(&app.Error{Code: "not_registered"}).Msg("test")
+
+		// The last \n at the end of this string is important
+		expected := strings.NewReader(`
+# HELP errors Number of errors grouped by code
+# TYPE errors counter
+errors{code="unregistered"} 1
+errors{code="zerolog_rate_limited"} 1
+`)
+		if err := testutil.GatherAndCompare(registry, expected, "errors"); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("labels", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		metricsWriter := NewErrorMetricsWriterWithOptions(registry, "errors",
+			WithLabels(CodeLabel("error.code"), LoggerNameLabel("logger"), OpLabel("error"), CallerPackageLabel(), FieldLabel("error", "tenant")),
+		)
+		multi := zerolog.MultiLevelWriter(metricsWriter, io.Discard)
+
+		log := zerolog.New(multi).With().Caller().Logger()
+		sublogger := log.With().Str("logger", "users").Logger()
+
+		sublogger.Error().Err(app.OpError("users.Get", &app.Error{Op: "db.Query", Code: app.ENOTFOUND, Fields: map[string]interface{}{"tenant": "acme"}})).Msg("test")
+		sublogger.Error().Err(app.OpError("users.Get", &app.Error{Op: "db.Query", Code: app.ENOTFOUND, Fields: map[string]interface{}{"tenant": "acme"}})).Msg("test")
+		log.Error().Msg("test")
+
+		// The last \n at the end of this string is important
+		expected := strings.NewReader(`
+# HELP errors Number of errors grouped by code, logger, op, package, tenant
+# TYPE errors counter
+errors{code="none",logger="none",op="none",package="zerolog",tenant="none"} 1
+errors{code="not_found",logger="users",op="users.Get",package="zerolog",tenant="acme"} 2
+`)
+		if err := testutil.GatherAndCompare(registry, expected, "errors"); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("cardinality limit", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		metricsWriter := NewErrorMetricsWriterWithOptions(registry, "errors",
+			WithLabels(FieldLabel("error", "tenant"), FieldLabel("error", "endpoint")),
+			WithMaxLabelValues(2),
+			WithLabelValues("endpoint", "/users"),
+		)
+		log := zerolog.New(zerolog.MultiLevelWriter(metricsWriter, io.Discard))
+
+		for _, fields := range []map[string]interface{}{
+			{"tenant": "a", "endpoint": "/users"},
+			{"tenant": "b", "endpoint": "/orders"},
+			{"tenant": "c", "endpoint": "/users"},
+			{"tenant": "a"},
+		} {
+			log.Error().Err(&app.Error{Code: app.ETEST, Fields: fields}).Msg("test")
+		}
+
+		// The last \n at the end of this string is important
+		expected := strings.NewReader(`
+# HELP errors Number of errors grouped by tenant, endpoint
+# TYPE errors counter
+errors{endpoint="/users",tenant="a"} 1
+errors{endpoint="/users",tenant="other"} 1
+errors{endpoint="none",tenant="a"} 1
+errors{endpoint="other",tenant="b"} 1
+`)
+		if err := testutil.GatherAndCompare(registry, expected, "errors"); err != nil {
+			t.Fatal(err)
+		}
+	})
+
 	t.Run("don't send metric if level >= Error", func(t *testing.T) {
 		registry := prometheus.NewRegistry()
 		metricsWriter := NewErrorMetricsWriter(registry, "error.code", "errors")
@@ -26,9 +26,41 @@ func (e err) MarshalZerologObject(event *zerolog.Event) {
 	}
 
 	event.Str("msg", app.ErrorMessageDefault(e.origin, ""))
+	if detail := app.ErrorDetail(e.origin); detail != "" {
+		event.Str("detail", detail)
+	}
 	event.Str("code", app.ErrorCode(e.origin).String())
 	event.Strs("trace", app.ErrorTrace(e.origin))
-	if fields := app.ErrorFields(e.origin); len(fields) > 0 {
+	if fields := app.RedactedErrorFields(e.origin); len(fields) > 0 {
 		event.Dict("fields", zerolog.Dict().Fields(fields))
 	}
+	var errs app.Errors
+	if errors.As(e.origin, &errs) {
+		arr := zerolog.Arr()
+		for _, origin := range errs {
+			if origin != nil {
+				arr.Object(err{origin: origin})
+			}
+		}
+		event.Array("errors", arr)
+	}
+	if stack := app.ErrorStack(e.origin); len(stack) > 0 {
+		event.Strs("stack", stack.Strings())
+	}
+}
+
+// FingerprintKey is the key of the fingerprint field.
+const FingerprintKey = "fingerprint"
+
+// Fingerprint returns the function adding app.Fingerprint of the error to the event, e.g.
+//
+//	log.Error().Func(zerolog.Fingerprint(err)).Err(err).Send()
+//
+// Nothing is added if the error is nil.
+func Fingerprint(err error, opts ...app.FingerprintOption) func(event *zerolog.Event) {
+	return func(event *zerolog.Event) {
+		if err != nil {
+			event.Str(FingerprintKey, app.Fingerprint(err, opts...))
+		}
+	}
 }
@@ -1,8 +1,13 @@
 package zerolog
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"os"
+	"strings"
+	"testing"
 
 	"github.com/MrEhbr/app"
 	"github.com/rs/zerolog"
@@ -22,9 +27,59 @@ func ExampleNew() {
 	log.Error().Err(errors.New("foo")).Msg("std error")
 	log.Error().Err(app.ErrorWithCode(errors.New("foo"), app.ETEST)).Msg("wrapped std error")
 	log.Error().Err(&app.Error{Op: "test", Code: app.ETEST, Message: "foo", Fields: map[string]interface{}{"foo": "bar"}}).Msg("error with fields")
+	log.Error().Err(app.OpError("batch", app.Join(
+		&app.Error{Op: "foo", Code: app.EINVALID, Message: "invalid", Fields: map[string]interface{}{"foo": "bar"}},
+		&app.Error{Op: "bar", Code: app.ENOTFOUND, Message: "not found"},
+	))).Msg("multiple errors")
+	log.Error().Err(app.NewValidation().Add("name", "required", "", "name is required").Err()).Msg("validation error")
+	ctx := app.WithFields(context.Background(), map[string]interface{}{"request_id": "1", "tenant": "acme"})
+	log.Error().Err(app.OpErrorCtx(ctx, "test", &app.Error{Code: app.ETEST, Message: "foo", Fields: map[string]interface{}{"tenant": "error"}})).Msg("error with context fields")
+	log.Error().Err(&app.Error{Op: "test", Code: app.ETEST, Message: "foo", Fields: map[string]interface{}{"token": "abc", "address": app.Sensitive("Baker Street")}}).Msg("error with sensitive fields")
+	log.Error().Err(&app.Error{Op: "test", Code: app.ETEST, Message: "foo", Detail: "bar"}).Msg("error with detail")
+	fpErr := &app.Error{Op: "test", Code: app.ETEST, Message: "foo"}
+	log.Error().Err(fpErr).Func(Fingerprint(fpErr)).Msg("error with fingerprint")
 
 	// Output: {"level":"error","message":"nil error"}
 	// {"level":"error","error":{"msg":"foo"},"message":"std error"}
 	// {"level":"error","error":{"msg":"foo","code":"test_error_code","trace":["github.com/MrEhbr/app/log/zerolog.ExampleNew"]},"message":"wrapped std error"}
 	// {"level":"error","error":{"msg":"foo","code":"test_error_code","trace":["test"],"fields":{"foo":"bar"}},"message":"error with fields"}
+	// {"level":"error","error":{"msg":"not found","code":"not_found","trace":["batch","bar"],"fields":{"foo":"bar"},"errors":[{"msg":"invalid","code":"invalid","trace":["foo"],"fields":{"foo":"bar"}},{"msg":"not found","code":"not_found","trace":["bar"]}]},"message":"multiple errors"}
+	// {"level":"error","error":{"msg":"validation failed","code":"invalid","trace":["github.com/MrEhbr/app/log/zerolog.ExampleNew"],"fields":{"violations":[{"field":"name","rule":"required","value":"","message":"name is required"}]}},"message":"validation error"}
+	// {"level":"error","error":{"msg":"foo","code":"test_error_code","trace":["test"],"fields":{"request_id":"1","tenant":"error"}},"message":"error with context fields"}
+	// {"level":"error","error":{"msg":"foo","code":"test_error_code","trace":["test"],"fields":{"address":"[REDACTED]","token":"[REDACTED]"}},"message":"error with sensitive fields"}
+	// {"level":"error","error":{"msg":"foo","detail":"bar","code":"test_error_code","trace":["test"]},"message":"error with detail"}
+	// {"level":"error","error":{"msg":"foo","code":"test_error_code","trace":["test"]},"fingerprint":"d163a4bbf1713acc","message":"error with fingerprint"}
+}
+
+func TestErrorMarshaler_stack(t *testing.T) {
+	originalErrorMarshalFunc := zerolog.ErrorMarshalFunc
+	defer func() {
+		zerolog.ErrorMarshalFunc = originalErrorMarshalFunc
+	}()
+
+	zerolog.ErrorMarshalFunc = ErrorMarshaler
+	app.EnableStackCapture(true)
+	defer app.EnableStackCapture(false)
+
+	var buf bytes.Buffer
+	log := zerolog.New(&buf)
+	log.Error().Err(app.ErrorWithCode(errors.New("foo"), app.ETEST)).Msg("test")
+
+	var entry struct {
+		Error struct {
+			Stack []string `json:"stack"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entry.Error.Stack) == 0 {
+		t.Fatalf("stack is not logged: %s", buf.String())
+	}
+
+	const want = "github.com/MrEhbr/app/log/zerolog.TestErrorMarshaler_stack "
+	if !strings.HasPrefix(entry.Error.Stack[0], want) {
+		t.Fatalf("first frame want prefix: %s, got: %s", want, entry.Error.Stack[0])
+	}
 }
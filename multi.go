@@ -0,0 +1,153 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// codePriority is the order of codes used to pick the dominant code of Errors.
+var codePriority = []Code{
+	EINTERNAL,
+	EBEHAVIOUR,
+	ECANNOTENCODE,
+	EUNSUPPORTED,
+	EUNAUTHENTICATED,
+	EPERMISSIONDENIED,
+	ECONFLICT,
+	EALREADYEXISTS,
+	ENOTFOUND,
+	EINVALID,
+	ECANNOTDECODE,
+	ECANNOTPARSE,
+	ENOTMODIFIED,
+	ETEST,
+}
+
+// SetCodePriority sets the order of codes used to pick the dominant code of Errors,
+// the first code is the most dominant, codes out of the list are less dominant than any in it.
+// It's not safe for concurrent use, call it on application initialization.
+func SetCodePriority(codes ...Code) {
+	codePriority = append([]Code{}, codes...)
+}
+
+func priority(code Code) int {
+	for i, c := range codePriority {
+		if c == code {
+			return i
+		}
+	}
+
+	return len(codePriority)
+}
+
+// Errors is an aggregate of errors.
+type Errors []error
+
+// Join returns Errors of non nil errs, or nil if there are no such errors.
+func Join(errs ...error) error {
+	res := make(Errors, 0, len(errs))
+	for _, err := range errs {
+		if err != nil {
+			res = append(res, err)
+		}
+	}
+
+	if len(res) == 0 {
+		return nil
+	}
+
+	return res
+}
+
+// Error returns messages of the errors separated by "; ".
+func (es Errors) Error() string {
+	msgs := make([]string, 0, len(es))
+	for _, err := range es {
+		if err != nil {
+			msgs = append(msgs, err.Error())
+		}
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+func (es Errors) Unwrap() []error { return es }
+
+func (es Errors) Is(target error) bool {
+	for _, err := range es {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Code returns the dominant code of the errors, EINTERNAL if there are no errors.
+func (es Errors) Code() Code {
+	dominant := es.dominant()
+	if dominant == nil {
+		return EINTERNAL
+	}
+
+	return ErrorCode(dominant)
+}
+
+// dominant returns the first error with the most dominant code.
+func (es Errors) dominant() error {
+	var (
+		res  error
+		best int
+	)
+	for _, err := range es {
+		if err == nil {
+			continue
+		}
+
+		if p := priority(ErrorCode(err)); res == nil || p < best {
+			res, best = err, p
+		}
+	}
+
+	return res
+}
+
+// Format implements fmt.Formatter, %+v prints every error in verbose form.
+func (es Errors) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			_, _ = io.WriteString(s, es.verbose())
+			return
+		}
+		_, _ = io.WriteString(s, es.Error())
+	case 's':
+		_, _ = io.WriteString(s, es.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", es.Error())
+	default:
+		fmt.Fprintf(s, "%%!%c(app.Errors=%s)", verb, es.Error())
+	}
+}
+
+func (es Errors) verbose() string {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "%d errors occurred:", len(es))
+	for _, err := range es {
+		if err == nil {
+			continue
+		}
+
+		buf.WriteString("\n\t* ")
+		buf.WriteString(strings.ReplaceAll(fmt.Sprintf("%+v", err), "\n", "\n\t  "))
+	}
+
+	return buf.String()
+}
+
+var (
+	_ error         = Errors{}
+	_ fmt.Formatter = Errors{}
+)
@@ -0,0 +1,123 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestJoin(t *testing.T) {
+	if err := Join(nil, nil); err != nil {
+		t.Fatalf("want nil, got: %v", err)
+	}
+
+	err := Join(errors.New("foo"), nil, errors.New("bar"))
+	if got := err.Error(); got != "foo; bar" {
+		t.Fatalf("Error() want: foo; bar, got: %s", got)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	errs := Join(
+		&Error{Op: "validate", Code: EINVALID, Message: "invalid name", Fields: map[string]interface{}{"name": ""}},
+		OpError("load", &Error{Op: "query", Code: EINTERNAL, Message: "db is down", Fields: map[string]interface{}{"table": "users"}}),
+		&Error{Op: "find", Code: ENOTFOUND},
+	)
+	err := OpError("batch", errs)
+
+	if !errors.Is(err, &Error{}) {
+		t.Fatal("errors.Is returned false")
+	}
+
+	if got := ErrorCode(err); got != EINTERNAL {
+		t.Fatalf("ErrorCode want: %s, got: %s", EINTERNAL, got)
+	}
+
+	if got := ErrorMessage(err); got != "db is down" {
+		t.Fatalf("ErrorMessage want: db is down, got: %s", got)
+	}
+
+	if diff := cmp.Diff([]string{"batch", "load", "query"}, ErrorTrace(err)); diff != "" {
+		t.Errorf("ErrorTrace() mismatch (-want +got):\n%s", diff)
+	}
+
+	wantTraces := [][]string{
+		{"batch", "validate"},
+		{"batch", "load", "query"},
+		{"batch", "find"},
+	}
+	if diff := cmp.Diff(wantTraces, ErrorTraces(err)); diff != "" {
+		t.Errorf("ErrorTraces() mismatch (-want +got):\n%s", diff)
+	}
+
+	wantFields := map[string]interface{}{"name": "", "table": "users"}
+	if diff := cmp.Diff(wantFields, ErrorFields(err)); diff != "" {
+		t.Errorf("ErrorFields() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestErrors_Code(t *testing.T) {
+	tests := []struct {
+		errs Errors
+		want Code
+	}{
+		{
+			errs: Errors{},
+			want: EINTERNAL,
+		},
+		{
+			errs: Errors{nil},
+			want: EINTERNAL,
+		},
+		{
+			errs: Errors{&Error{Code: ENOTFOUND}, &Error{Code: EINVALID}},
+			want: ENOTFOUND,
+		},
+		{
+			errs: Errors{&Error{Code: ETEST}, errors.New("foo")},
+			want: EINTERNAL,
+		},
+		{
+			errs: Errors{&Error{Code: ETEST}, fmt.Errorf("%w", Errors{&Error{Code: EPERMISSIONDENIED}})},
+			want: EPERMISSIONDENIED,
+		},
+	}
+
+	for i, tt := range tests {
+		if got := tt.errs.Code(); got != tt.want {
+			t.Fatalf("%d. Code want: %s, got: %s", i, tt.want, got)
+		}
+	}
+}
+
+func TestSetCodePriority(t *testing.T) {
+	original := codePriority
+	defer func() {
+		codePriority = original
+	}()
+
+	SetCodePriority(EINVALID, ENOTFOUND)
+
+	errs := Errors{&Error{Code: EINTERNAL}, &Error{Code: ENOTFOUND}, &Error{Code: EINVALID}}
+	if got := errs.Code(); got != EINVALID {
+		t.Fatalf("Code want: %s, got: %s", EINVALID, got)
+	}
+}
+
+func TestErrors_Format(t *testing.T) {
+	err := OpError("batch", Errors{
+		&Error{Op: "foo", Code: EINVALID, Message: "invalid"},
+		OpError("bar", errors.New("baz")),
+	})
+
+	want := "batch\n2 errors occurred:\n\t* foo: <invalid> invalid\n\t* bar\n\t  baz"
+	if got := fmt.Sprintf("%+v", err); got != want {
+		t.Fatalf("want:\n%s\ngot:\n%s", want, got)
+	}
+
+	if got := fmt.Sprintf("%v", err); got != "batch: foo: <invalid> invalid; bar: baz" {
+		t.Fatalf("unexpected %%v: %s", got)
+	}
+}
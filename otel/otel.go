@@ -0,0 +1,113 @@
+// Package otel records app errors on OpenTelemetry spans.
+package otel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/MrEhbr/app"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Attribute keys of the recorded error.
+const (
+	CodeKey    = attribute.Key("error.code")
+	OpTraceKey = attribute.Key("error.op_trace")
+	// FieldsKeyPrefix is the prefix of Error.Fields keys.
+	FieldsKeyPrefix = "error.fields."
+)
+
+// RecordError records the err on the span of the ctx.
+// The code, the op trace and fields of the err are set as span attributes,
+// the exception event is added and the span status is set to codes.Error
+// if the HTTP status of the code is 5xx or the code is unregistered.
+func RecordError(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+
+	attrs := Attributes(err)
+	span.SetAttributes(attrs...)
+	span.RecordError(err, trace.WithAttributes(attrs...))
+	if isServerError(err) {
+		span.SetStatus(codes.Error, app.ErrorMessage(err))
+	}
+}
+
+// WithSpan calls fn in the span named after the name, or the caller function if the name is empty,
+// and records the returned error.
+func WithSpan(ctx context.Context, tracer trace.Tracer, name string, fn func(ctx context.Context) error, opts ...trace.SpanStartOption) error {
+	if name == "" {
+		name = app.CallerFunctionName()
+	}
+
+	ctx, span := tracer.Start(ctx, name, opts...)
+	defer span.End()
+
+	err := fn(ctx)
+	RecordError(ctx, err)
+
+	return err
+}
+
+// Attributes returns span attributes of the err.
+func Attributes(err error) []attribute.KeyValue {
+	fields := app.RedactedErrorFields(err)
+	attrs := make([]attribute.KeyValue, 0, 2+len(fields))
+	attrs = append(attrs,
+		CodeKey.String(app.ErrorCode(err).String()),
+		OpTraceKey.StringSlice(app.ErrorTrace(err)),
+	)
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		attrs = append(attrs, fieldAttribute(FieldsKeyPrefix+k, fields[k]))
+	}
+
+	return attrs
+}
+
+func fieldAttribute(key string, v interface{}) attribute.KeyValue {
+	switch v := v.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int64:
+		return attribute.Int64(key, v)
+	case float64:
+		return attribute.Float64(key, v)
+	case []string:
+		return attribute.StringSlice(key, v)
+	case fmt.Stringer:
+		return attribute.Stringer(key, v)
+	}
+
+	if data, err := json.Marshal(v); err == nil {
+		return attribute.String(key, string(data))
+	}
+
+	return attribute.String(key, fmt.Sprint(v))
+}
+
+// isServerError reports whether the err is caused by the server.
+func isServerError(err error) bool {
+	info, ok := app.LookupCode(app.ErrorCode(err))
+	return !ok || info.HTTPStatus == 0 || info.HTTPStatus >= http.StatusInternalServerError
+}
@@ -0,0 +1,122 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/MrEhbr/app"
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTracer() (*tracetest.InMemoryExporter, *sdktrace.TracerProvider) {
+	exporter := tracetest.NewInMemoryExporter()
+	return exporter, sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+}
+
+func TestWithSpan(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus codes.Code
+		wantAttrs  []attribute.KeyValue
+	}{
+		{
+			name:       "no error",
+			wantStatus: codes.Unset,
+		},
+		{
+			name: "client error",
+			err: app.OpError("users.Get", &app.Error{
+				Op:     "db.Query",
+				Code:   app.ENOTFOUND,
+				Fields: map[string]interface{}{"id": 1, "tenant": "acme"},
+			}),
+			wantStatus: codes.Unset,
+			wantAttrs: []attribute.KeyValue{
+				CodeKey.String("not_found"),
+				OpTraceKey.StringSlice([]string{"users.Get", "db.Query"}),
+				attribute.Int("error.fields.id", 1),
+				attribute.String("error.fields.tenant", "acme"),
+			},
+		},
+		{
+			name:       "server error",
+			err:        &app.Error{Op: "db.Query", Code: app.EINTERNAL, Message: "connection refused"},
+			wantStatus: codes.Error,
+			wantAttrs: []attribute.KeyValue{
+				CodeKey.String("internal"),
+				OpTraceKey.StringSlice([]string{"db.Query"}),
+			},
+		},
+		{
+			name:       "std error",
+			err:        errors.New("foo"),
+			wantStatus: codes.Error,
+			wantAttrs: []attribute.KeyValue{
+				CodeKey.String("internal"),
+				OpTraceKey.StringSlice([]string{}),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter, provider := newTracer()
+
+			err := WithSpan(context.Background(), provider.Tracer("test"), "", func(ctx context.Context) error {
+				return tt.err
+			})
+			if err != tt.err {
+				t.Fatalf("error want: %v, got: %v", tt.err, err)
+			}
+
+			spans := exporter.GetSpans()
+			if len(spans) != 1 {
+				t.Fatalf("want 1 span, got: %d", len(spans))
+			}
+			span := spans[0]
+
+			const wantName = "github.com/MrEhbr/app/otel.TestWithSpan.func1"
+			if span.Name != wantName {
+				t.Fatalf("name want: %s, got: %s", wantName, span.Name)
+			}
+
+			if span.Status.Code != tt.wantStatus {
+				t.Fatalf("status want: %s, got: %s", tt.wantStatus, span.Status.Code)
+			}
+
+			if diff := cmp.Diff(tt.wantAttrs, span.Attributes, cmp.Comparer(func(a, b attribute.Value) bool { return a.Emit() == b.Emit() })); diff != "" {
+				t.Fatalf("attributes mismatch (-want +got):\n%s", diff)
+			}
+
+			if tt.err == nil {
+				if len(span.Events) != 0 {
+					t.Fatalf("want no events, got: %v", span.Events)
+				}
+				return
+			}
+
+			if len(span.Events) != 1 || span.Events[0].Name != "exception" {
+				t.Fatalf("want exception event, got: %v", span.Events)
+			}
+
+			attrs := attribute.NewSet(span.Events[0].Attributes...)
+			if v, _ := attrs.Value("exception.message"); v.AsString() != tt.err.Error() {
+				t.Fatalf("exception message want: %s, got: %s", tt.err.Error(), v.AsString())
+			}
+			if v, _ := attrs.Value(CodeKey); v.AsString() != app.ErrorCode(tt.err).String() {
+				t.Fatalf("event code want: %s, got: %s", app.ErrorCode(tt.err), v.AsString())
+			}
+		})
+	}
+}
+
+func TestRecordError_notRecording(t *testing.T) {
+	// must not panic without span
+	RecordError(context.Background(), &app.Error{Code: app.EINTERNAL})
+}
@@ -0,0 +1,112 @@
+package app
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// PanicKey is the key of the panic value in Error.Fields of recovered panics.
+const PanicKey = "panic"
+
+// RecoverOption configures Recover.
+type RecoverOption func(*recoverOptions)
+
+type recoverOptions struct {
+	repanic bool
+}
+
+// Repanic panics again with the original value after the error is set,
+// e.g. to crash the process after the panic is logged by the deferred functions called earlier.
+func Repanic() RecoverOption {
+	return func(o *recoverOptions) {
+		o.repanic = true
+	}
+}
+
+// Recover converts the panic to EBEHAVIOUR error and sets it to the err, it must be deferred directly:
+//
+//	func Do() (err error) {
+//		defer app.Recover(&err)
+//		...
+//	}
+//
+// The error has the panic value in Fields by PanicKey, the stack and the Op of the panicking function.
+// If the panic value is an error, it's wrapped.
+func Recover(err *error, opts ...RecoverOption) {
+	v := recover()
+	if v == nil {
+		return
+	}
+
+	var o recoverOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	*err = panicError(v)
+	if o.repanic {
+		panic(v)
+	}
+}
+
+// SafeGo calls the fn in a new goroutine recovering panics with Recover.
+// The returned channel receives the error of the fn and is closed then.
+func SafeGo(fn func() error, opts ...RecoverOption) <-chan error {
+	errc := make(chan error, 1)
+	go func() {
+		var err error
+		defer func() {
+			errc <- err
+			close(errc)
+		}()
+		defer Recover(&err, opts...)
+
+		err = fn()
+	}()
+
+	return errc
+}
+
+// panicError returns EBEHAVIOUR error of the panic value v, it must be called from the deferred function.
+func panicError(v interface{}) *Error {
+	pcs := make([]uintptr, maxStackDepth)
+	pcs = pcs[:runtime.Callers(1, pcs)]
+
+	e := &Error{
+		Code:   EBEHAVIOUR,
+		Detail: fmt.Sprint("panic: ", v),
+		Fields: map[string]interface{}{PanicKey: v},
+	}
+	if err, ok := v.(error); ok {
+		e.Err = err
+		e.Fields[PanicKey] = err.Error()
+	}
+
+	e.Op, e.Stack = panicFrame(pcs)
+
+	return e
+}
+
+// panicFrame returns the function that panicked and the stack starting from it.
+// The function is the first frame after runtime.gopanic that doesn't belong to the runtime,
+// e.g. runtime.panicmem for nil pointer dereference.
+func panicFrame(pcs []uintptr) (string, Stack) {
+	panicking := false
+	for i := range pcs {
+		frame, _ := runtime.CallersFrames(pcs[i : i+1]).Next()
+		switch {
+		case frame.Function == "runtime.gopanic":
+			panicking = true
+		case panicking && !strings.HasPrefix(frame.Function, "runtime."):
+			return frame.Function, pcs[i:]
+		}
+	}
+
+	if len(pcs) == 0 {
+		return "", nil
+	}
+
+	frame, _ := runtime.CallersFrames(pcs).Next()
+	return frame.Function, pcs
+}
@@ -0,0 +1,121 @@
+package app
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func recoverValue(v interface{}) (err error) {
+	defer Recover(&err)
+	panic(v)
+}
+
+func recoverNilPointer() (err error) {
+	defer Recover(&err)
+	var e *Error
+	return e.Err
+}
+
+func TestRecover(t *testing.T) {
+	foo := errors.New("foo")
+
+	tests := []struct {
+		name       string
+		fn         func() error
+		wantOp     string
+		wantFields map[string]interface{}
+		wantErr    error
+	}{
+		{
+			name:       "value",
+			fn:         func() error { return recoverValue("foo") },
+			wantOp:     "github.com/MrEhbr/app.recoverValue",
+			wantFields: map[string]interface{}{PanicKey: "foo"},
+		},
+		{
+			name:       "error",
+			fn:         func() error { return recoverValue(foo) },
+			wantOp:     "github.com/MrEhbr/app.recoverValue",
+			wantFields: map[string]interface{}{PanicKey: "foo"},
+			wantErr:    foo,
+		},
+		{
+			name:       "runtime error",
+			fn:         recoverNilPointer,
+			wantOp:     "github.com/MrEhbr/app.recoverNilPointer",
+			wantFields: map[string]interface{}{PanicKey: "runtime error: invalid memory address or nil pointer dereference"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.fn()
+			if code := ErrorCode(err); code != EBEHAVIOUR {
+				t.Fatalf("ErrorCode() want: %s, got: %s", EBEHAVIOUR, code)
+			}
+
+			if diff := cmp.Diff([]string{tt.wantOp}, ErrorTrace(err)); diff != "" {
+				t.Fatalf("ErrorTrace() mismatch (-want +got):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tt.wantFields, ErrorFields(err)); diff != "" {
+				t.Fatalf("ErrorFields() mismatch (-want +got):\n%s", diff)
+			}
+
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("want wrapped: %v, got: %v", tt.wantErr, err)
+			}
+
+			stack := ErrorStack(err).Strings()
+			if len(stack) == 0 || !strings.HasPrefix(stack[0], tt.wantOp+" ") {
+				t.Fatalf("stack want first frame: %s, got: %v", tt.wantOp, stack)
+			}
+		})
+	}
+}
+
+func TestRecover_noPanic(t *testing.T) {
+	err := func() (err error) {
+		defer Recover(&err)
+		return ErrorWithCode(errors.New("foo"), ETEST)
+	}()
+
+	if code := ErrorCode(err); code != ETEST {
+		t.Fatalf("ErrorCode() want: %s, got: %s", ETEST, code)
+	}
+}
+
+func TestRecover_repanic(t *testing.T) {
+	var err error
+	defer func() {
+		if v := recover(); v != "foo" {
+			t.Fatalf("recover() want: foo, got: %v", v)
+		}
+		if code := ErrorCode(err); code != EBEHAVIOUR {
+			t.Fatalf("ErrorCode() want: %s, got: %s", EBEHAVIOUR, code)
+		}
+	}()
+
+	func() {
+		defer Recover(&err, Repanic())
+		panic("foo")
+	}()
+}
+
+func TestSafeGo(t *testing.T) {
+	if err := <-SafeGo(func() error { return nil }); err != nil {
+		t.Fatalf("want nil, got: %v", err)
+	}
+
+	errc := SafeGo(func() error { panic("foo") })
+	if code := ErrorCode(<-errc); code != EBEHAVIOUR {
+		t.Fatalf("ErrorCode() want: %s, got: %s", EBEHAVIOUR, code)
+	}
+
+	if _, ok := <-errc; ok {
+		t.Fatal("channel is not closed")
+	}
+}
@@ -0,0 +1,161 @@
+package app
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RedactedMask replaces sensitive values.
+const RedactedMask = "[REDACTED]"
+
+// SensitiveValue holds a value that must not be exposed in logs and responses.
+// It's rendered as RedactedMask by fmt and encoding/json, the raw value is available with Raw.
+type SensitiveValue struct {
+	raw interface{}
+}
+
+// Sensitive marks the v as sensitive, so it's redacted regardless of the field key.
+func Sensitive(v interface{}) SensitiveValue {
+	return SensitiveValue{raw: v}
+}
+
+// Raw returns the raw value.
+func (v SensitiveValue) Raw() interface{} {
+	return v.raw
+}
+
+// String implements fmt.Stringer.
+func (v SensitiveValue) String() string {
+	return RedactedMask
+}
+
+// GoString implements fmt.GoStringer.
+func (v SensitiveValue) GoString() string {
+	return RedactedMask
+}
+
+// MarshalJSON implements json.Marshaler.
+func (v SensitiveValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(RedactedMask)
+}
+
+// RedactPolicy defines sensitive fields of Error.Fields.
+type RedactPolicy struct {
+	// Case-insensitive keys of sensitive fields.
+	Keys []string
+	// Patterns of keys of sensitive fields.
+	Patterns []*regexp.Regexp
+	// Replace values with their HMAC keyed with HashKey instead of RedactedMask,
+	// so equal values can be correlated without exposing them.
+	Hash bool
+	// Secret key of the HMAC, values are replaced with RedactedMask if it's empty,
+	// because plain hashes of emails or phones are easy to brute force.
+	HashKey []byte
+}
+
+// DefaultRedactPolicy is the policy used by default.
+var DefaultRedactPolicy = RedactPolicy{
+	Keys: []string{"authorization", "cookie", "email", "phone", "card_number", "cvv"},
+	Patterns: []*regexp.Regexp{
+		regexp.MustCompile(`(?i)(password|passwd|secret|token|api_?key|private_?key|credential)`),
+	},
+}
+
+var redactPolicy = DefaultRedactPolicy
+
+// SetRedactPolicy sets the policy used by RedactedErrorFields.
+// It's not safe for concurrent use, call it on application initialization.
+func SetRedactPolicy(p RedactPolicy) {
+	redactPolicy = p
+}
+
+// RedactedErrorFields returns ErrorFields of the err with sensitive values redacted by the policy set with SetRedactPolicy.
+func RedactedErrorFields(err error) map[string]interface{} {
+	return redactPolicy.Redact(ErrorFields(err))
+}
+
+// IsSensitive reports whether the field with the key is sensitive.
+func (p RedactPolicy) IsSensitive(key string) bool {
+	for _, k := range p.Keys {
+		if strings.EqualFold(k, key) {
+			return true
+		}
+	}
+
+	for _, pattern := range p.Patterns {
+		if pattern.MatchString(key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Redact returns the copy of the fields with sensitive values redacted.
+// Values of violations of sensitive fields are redacted as well.
+func (p RedactPolicy) Redact(fields map[string]interface{}) map[string]interface{} {
+	if fields == nil {
+		return nil
+	}
+
+	res := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		switch {
+		case p.IsSensitive(k):
+			res[k] = p.redact(v)
+		case k == ViolationsKey:
+			res[k] = p.redactViolations(v)
+		default:
+			res[k] = p.redactSensitive(v)
+		}
+	}
+
+	return res
+}
+
+func (p RedactPolicy) redactViolations(v interface{}) interface{} {
+	violations, ok := v.([]Violation)
+	if !ok {
+		return p.redactSensitive(v)
+	}
+
+	res := make([]Violation, len(violations))
+	for i, violation := range violations {
+		if violation.Value != nil && p.IsSensitive(violation.Field) {
+			violation.Value = p.redact(violation.Value)
+		} else {
+			violation.Value = p.redactSensitive(violation.Value)
+		}
+		res[i] = violation
+	}
+
+	return res
+}
+
+// redactSensitive redacts the v if it's SensitiveValue.
+func (p RedactPolicy) redactSensitive(v interface{}) interface{} {
+	if _, ok := v.(SensitiveValue); ok {
+		return p.redact(v)
+	}
+
+	return v
+}
+
+func (p RedactPolicy) redact(v interface{}) string {
+	if !p.Hash || len(p.HashKey) == 0 {
+		return RedactedMask
+	}
+
+	if sensitive, ok := v.(SensitiveValue); ok {
+		v = sensitive.raw
+	}
+
+	mac := hmac.New(sha256.New, p.HashKey)
+	_, _ = mac.Write([]byte(fmt.Sprint(v)))
+	return "hmac-sha256:" + hex.EncodeToString(mac.Sum(nil)[:8])
+}
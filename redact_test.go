@@ -0,0 +1,120 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSensitive(t *testing.T) {
+	v := Sensitive("secret@example.com")
+
+	if got := fmt.Sprintf("%v %s %+v %#v", v, v, v, v); got != "[REDACTED] [REDACTED] [REDACTED] [REDACTED]" {
+		t.Fatalf("fmt want masked, got: %s", got)
+	}
+
+	data, err := json.Marshal(map[string]interface{}{"email": v})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); got != `{"email":"[REDACTED]"}` {
+		t.Fatalf("json want masked, got: %s", got)
+	}
+
+	if got := v.Raw(); got != "secret@example.com" {
+		t.Fatalf("Raw want: secret@example.com, got: %v", got)
+	}
+}
+
+func TestRedactPolicy_Redact(t *testing.T) {
+	fields := map[string]interface{}{
+		"user_id":      1,
+		"Email":        "john@example.com",
+		"access_token": "abc",
+		"address":      Sensitive("Baker Street"),
+		ViolationsKey: []Violation{
+			{Field: "password", Rule: "min", Value: "123", Message: "too short"},
+			{Field: "name", Rule: "required", Value: "", Message: "name is required"},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		policy RedactPolicy
+		want   map[string]interface{}
+	}{
+		{
+			name:   "default",
+			policy: DefaultRedactPolicy,
+			want: map[string]interface{}{
+				"user_id":      1,
+				"Email":        RedactedMask,
+				"access_token": RedactedMask,
+				"address":      RedactedMask,
+				ViolationsKey: []Violation{
+					{Field: "password", Rule: "min", Value: RedactedMask, Message: "too short"},
+					{Field: "name", Rule: "required", Value: "", Message: "name is required"},
+				},
+			},
+		},
+		{
+			name:   "hash",
+			policy: RedactPolicy{Keys: []string{"email"}, Patterns: []*regexp.Regexp{regexp.MustCompile("token$")}, Hash: true, HashKey: []byte("secret")},
+			want: map[string]interface{}{
+				"user_id":      1,
+				"Email":        "hmac-sha256:62f6d956c6a55341",
+				"access_token": "hmac-sha256:9946dad4e00e913f",
+				"address":      "hmac-sha256:72b029db7b085ce3",
+				ViolationsKey: []Violation{
+					{Field: "password", Rule: "min", Value: "123", Message: "too short"},
+					{Field: "name", Rule: "required", Value: "", Message: "name is required"},
+				},
+			},
+		},
+		{
+			name:   "hash without key",
+			policy: RedactPolicy{Keys: []string{"email"}, Patterns: []*regexp.Regexp{regexp.MustCompile("token$")}, Hash: true},
+			want: map[string]interface{}{
+				"user_id":      1,
+				"Email":        RedactedMask,
+				"access_token": RedactedMask,
+				"address":      RedactedMask,
+				ViolationsKey: []Violation{
+					{Field: "password", Rule: "min", Value: "123", Message: "too short"},
+					{Field: "name", Rule: "required", Value: "", Message: "name is required"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, tt.policy.Redact(fields)); diff != "" {
+				t.Fatalf("fields mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRedactedErrorFields(t *testing.T) {
+	defer SetRedactPolicy(DefaultRedactPolicy)
+	SetRedactPolicy(RedactPolicy{Keys: []string{"tenant"}})
+
+	err := OpError("outer", &Error{Code: ENOTFOUND, Fields: map[string]interface{}{"tenant": "acme", "id": 1}})
+
+	want := map[string]interface{}{"tenant": RedactedMask, "id": 1}
+	if diff := cmp.Diff(want, RedactedErrorFields(err)); diff != "" {
+		t.Fatalf("fields mismatch (-want +got):\n%s", diff)
+	}
+
+	if got := ErrorFields(err)["tenant"]; got != "acme" {
+		t.Fatalf("ErrorFields want raw value, got: %v", got)
+	}
+
+	if got, want := fmt.Sprintf("%+v", err), "outer\n<not_found> {id=1 tenant=[REDACTED]}"; got != want {
+		t.Fatalf("Format want: %s, got: %s", want, got)
+	}
+}
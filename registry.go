@@ -0,0 +1,125 @@
+package app
+
+import (
+	"sort"
+	"sync"
+)
+
+// Log levels of CodeInfo.
+const (
+	LevelDebug = "debug"
+	LevelInfo  = "info"
+	LevelWarn  = "warn"
+	LevelError = "error"
+)
+
+// CodeInfo describes an error code.
+type CodeInfo struct {
+	// Human-readable description of the code.
+	Description string
+	// Whether the operation failed with the code may be retried.
+	Retryable bool
+	// Whether the failure is temporary and may be resolved by itself.
+	Temporary bool
+	// HTTP status code of the code, see net/http.
+	HTTPStatus int
+	// gRPC status code of the code, see google.golang.org/grpc/codes.
+	GRPCCode uint32
+	// Level errors with the code should be logged at, one of LevelDebug, LevelInfo, LevelWarn, LevelError.
+	LogLevel string
+}
+
+var registry = struct {
+	sync.RWMutex
+	codes map[Code]CodeInfo
+}{
+	codes: map[Code]CodeInfo{
+		ECONFLICT:         {Description: "Action cannot be performed", HTTPStatus: 409, GRPCCode: 10, LogLevel: LevelWarn},                        // http.StatusConflict, codes.Aborted
+		EINTERNAL:         {Description: "Internal error", Retryable: true, Temporary: true, HTTPStatus: 500, GRPCCode: 13, LogLevel: LevelError}, // http.StatusInternalServerError, codes.Internal
+		EINVALID:          {Description: "Validation failed", HTTPStatus: 400, GRPCCode: 3, LogLevel: LevelWarn},                                  // http.StatusBadRequest, codes.InvalidArgument
+		ENOTFOUND:         {Description: "Entity not found", HTTPStatus: 404, GRPCCode: 5, LogLevel: LevelInfo},                                   // http.StatusNotFound, codes.NotFound
+		ENOTMODIFIED:      {Description: "Entity not modified", HTTPStatus: 304, GRPCCode: 9, LogLevel: LevelInfo},                                // http.StatusNotModified, codes.FailedPrecondition
+		EALREADYEXISTS:    {Description: "Entity already exists", HTTPStatus: 409, GRPCCode: 6, LogLevel: LevelWarn},                              // http.StatusConflict, codes.AlreadyExists
+		EPERMISSIONDENIED: {Description: "Permission denied", HTTPStatus: 403, GRPCCode: 7, LogLevel: LevelWarn},                                  // http.StatusForbidden, codes.PermissionDenied
+		EUNAUTHENTICATED:  {Description: "Unauthenticated", HTTPStatus: 401, GRPCCode: 16, LogLevel: LevelWarn},                                   // http.StatusUnauthorized, codes.Unauthenticated
+		ECANNOTDECODE:     {Description: "Data could not be decoded", HTTPStatus: 400, GRPCCode: 3, LogLevel: LevelWarn},                          // http.StatusBadRequest, codes.InvalidArgument
+		ECANNOTENCODE:     {Description: "Data could not be encoded", HTTPStatus: 500, GRPCCode: 13, LogLevel: LevelError},                        // http.StatusInternalServerError, codes.Internal
+		ECANNOTPARSE:      {Description: "Data could not be parsed", HTTPStatus: 400, GRPCCode: 3, LogLevel: LevelWarn},                           // http.StatusBadRequest, codes.InvalidArgument
+		EBEHAVIOUR:        {Description: "Undefined behavior", HTTPStatus: 500, GRPCCode: 13, LogLevel: LevelError},                               // http.StatusInternalServerError, codes.Internal
+		EUNSUPPORTED:      {Description: "Unsupported action", HTTPStatus: 501, GRPCCode: 12, LogLevel: LevelError},                               // http.StatusNotImplemented, codes.Unimplemented
+		ETEST:             {Description: "Test error", HTTPStatus: 418, GRPCCode: 2, LogLevel: LevelError},                                        // http.StatusTeapot, codes.Unknown
+	},
+}
+
+// RegisterCode registers the custom code with its info.
+// Returns EALREADYEXISTS error if the code is already registered.
+func RegisterCode(code Code, info CodeInfo) error {
+	if code == "" {
+		return &Error{Op: CurrentFunctionName(), Code: EINVALID, Message: "code is empty"}
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	if _, ok := registry.codes[code]; ok {
+		return &Error{
+			Op:      CurrentFunctionName(),
+			Code:    EALREADYEXISTS,
+			Message: "code already registered",
+			Fields:  map[string]interface{}{"code": code.String()},
+		}
+	}
+
+	registry.codes[code] = info
+
+	return nil
+}
+
+// MustRegisterCode is like RegisterCode but panics on error.
+func MustRegisterCode(code Code, info CodeInfo) {
+	if err := RegisterCode(code, info); err != nil {
+		panic(err)
+	}
+}
+
+// LookupCode returns the info of the registered code.
+func LookupCode(code Code) (CodeInfo, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	info, ok := registry.codes[code]
+	return info, ok
+}
+
+// Registered reports whether the code is registered.
+func (code Code) Registered() bool {
+	_, ok := LookupCode(code)
+	return ok
+}
+
+// UnregisteredLabel is the metric label of unregistered codes.
+const UnregisteredLabel = "unregistered"
+
+// MetricLabel returns the code as the metric label, or UnregisteredLabel if the code is not registered,
+// so cardinality of metrics stays bounded whatever codes errors have.
+func (code Code) MetricLabel() string {
+	if !code.Registered() {
+		return UnregisteredLabel
+	}
+
+	return code.String()
+}
+
+// RegisteredCodes returns all registered codes sorted.
+func RegisteredCodes() []Code {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	codes := make([]Code, 0, len(registry.codes))
+	for code := range registry.codes {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+
+	return codes
+}
@@ -0,0 +1,82 @@
+package app
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestRegisterCode(t *testing.T) {
+	const code Code = "rate_limited"
+	defer func() {
+		registry.Lock()
+		delete(registry.codes, code)
+		registry.Unlock()
+	}()
+
+	info := CodeInfo{Description: "Too many requests", Retryable: true, HTTPStatus: http.StatusTooManyRequests, GRPCCode: 8, LogLevel: LevelWarn}
+	if err := RegisterCode(code, info); err != nil {
+		t.Fatal(err)
+	}
+
+	got, ok := LookupCode(code)
+	if !ok {
+		t.Fatal("code is not registered")
+	}
+
+	if got != info {
+		t.Fatalf("info want: %+v, got: %+v", info, got)
+	}
+
+	if !code.Registered() {
+		t.Fatal("Registered returned false")
+	}
+
+	err := RegisterCode(code, CodeInfo{})
+	if ErrorCode(err) != EALREADYEXISTS {
+		t.Fatalf("duplicate want code: %s, got: %v", EALREADYEXISTS, err)
+	}
+
+	if err := RegisterCode(EINTERNAL, CodeInfo{}); ErrorCode(err) != EALREADYEXISTS {
+		t.Fatalf("built-in duplicate want code: %s, got: %v", EALREADYEXISTS, err)
+	}
+
+	if err := RegisterCode("", CodeInfo{}); ErrorCode(err) != EINVALID {
+		t.Fatalf("empty code want: %s, got: %v", EINVALID, err)
+	}
+}
+
+func TestMustRegisterCode(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("MustRegisterCode didn't panic")
+		}
+	}()
+
+	MustRegisterCode(ENOTFOUND, CodeInfo{})
+}
+
+func TestRegisteredCodes(t *testing.T) {
+	for _, code := range codePriority {
+		if _, ok := LookupCode(code); !ok {
+			t.Fatalf("built-in code %s is not registered", code)
+		}
+	}
+
+	if got, want := len(RegisteredCodes()), len(codePriority); got != want {
+		t.Fatalf("registered codes want: %d, got: %d", want, got)
+	}
+
+	if Code("foo").Registered() {
+		t.Fatal("unknown code is registered")
+	}
+}
+
+func TestCode_MetricLabel(t *testing.T) {
+	if got := ENOTFOUND.MetricLabel(); got != ENOTFOUND.String() {
+		t.Fatalf("registered code label want: %s, got: %s", ENOTFOUND, got)
+	}
+
+	if got := Code("foo").MetricLabel(); got != UnregisteredLabel {
+		t.Fatalf("unregistered code label want: %s, got: %s", UnregisteredLabel, got)
+	}
+}
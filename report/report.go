@@ -0,0 +1,52 @@
+// Package report forwards application errors to error tracking services.
+package report
+
+import (
+	"context"
+	"math/rand"
+
+	"github.com/MrEhbr/app"
+	"github.com/MrEhbr/app/internal/mapx"
+)
+
+// Reporter reports errors to an error tracking service.
+type Reporter interface {
+	// Report sends the err, errors skipped by sampling are not sent and nil is returned.
+	Report(ctx context.Context, err error) error
+}
+
+// SampleRates defines the share of errors reported per error code, from 0 to 1.
+// Errors with codes not in the rates are not reported.
+type SampleRates map[app.Code]float64
+
+// DefaultSampleRates reports all internal and undefined behavior errors.
+var DefaultSampleRates = SampleRates{
+	app.EINTERNAL:  1,
+	app.EBEHAVIOUR: 1,
+}
+
+// With returns a copy of s extended with overrides.
+func (s SampleRates) With(overrides SampleRates) SampleRates {
+	return mapx.Merge(s, overrides)
+}
+
+// Sampled reports whether the err should be reported, random is called for rates between 0 and 1.
+func (s SampleRates) Sampled(err error, random func() float64) bool {
+	if err == nil {
+		return false
+	}
+
+	rate := s[app.ErrorCode(err)]
+	switch {
+	case rate <= 0:
+		return false
+	case rate >= 1:
+		return true
+	}
+
+	if random == nil {
+		random = rand.Float64
+	}
+
+	return random() < rate
+}
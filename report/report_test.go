@@ -0,0 +1,65 @@
+package report
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/MrEhbr/app"
+)
+
+func TestSampleRates_Sampled(t *testing.T) {
+	rates := DefaultSampleRates.With(SampleRates{app.ECONFLICT: 0.5, app.EBEHAVIOUR: 0})
+
+	tests := []struct {
+		name   string
+		err    error
+		random float64
+		want   bool
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			want: false,
+		},
+		{
+			name: "internal",
+			err:  errors.New("foo"),
+			want: true,
+		},
+		{
+			name: "overridden",
+			err:  app.NewError(app.EBEHAVIOUR, "foo"),
+			want: false,
+		},
+		{
+			name: "not in rates",
+			err:  app.NewError(app.ENOTFOUND, "foo"),
+			want: false,
+		},
+		{
+			name:   "sampled",
+			err:    app.NewError(app.ECONFLICT, "foo"),
+			random: 0.4,
+			want:   true,
+		},
+		{
+			name:   "not sampled",
+			err:    app.NewError(app.ECONFLICT, "foo"),
+			random: 0.5,
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rates.Sampled(tt.err, func() float64 { return tt.random })
+			if got != tt.want {
+				t.Fatalf("Sampled() want: %t, got: %t", tt.want, got)
+			}
+		})
+	}
+
+	if _, ok := DefaultSampleRates[app.ECONFLICT]; ok {
+		t.Fatal("With() modified DefaultSampleRates")
+	}
+}
@@ -0,0 +1,283 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/MrEhbr/app"
+)
+
+const (
+	sentryClient      = "github.com/MrEhbr/app/report"
+	sentryVersion     = "7"
+	sentryContentType = "application/x-sentry-envelope"
+)
+
+// Tags of Sentry events.
+const (
+	CodeTag        = "error.code"
+	FingerprintTag = "error.fingerprint"
+)
+
+// Sentry reports errors to Sentry as envelopes.
+//
+// The code is sent as a tag, redacted fields as extras and ops of ErrorTrace as breadcrumbs.
+// The stack trace of the exception is the captured stack, if any,
+// otherwise pseudo-frames built from ops of ErrorTrace.
+//
+// The zero value has no DSN, so create Sentry with NewSentry.
+type Sentry struct {
+	// Client sends envelopes, http.DefaultClient if nil.
+	Client *http.Client
+	// Environment of events, e.g. "production".
+	Environment string
+	// Release of events, e.g. the version of the application.
+	Release string
+	// SampleRates of error codes, DefaultSampleRates if nil.
+	SampleRates SampleRates
+
+	dsn      string
+	endpoint string
+	key      string
+	random   func() float64
+	now      func() time.Time
+}
+
+// NewSentry returns the reporter sending events to the project of the DSN,
+// e.g. "https://public@sentry.example.com/1".
+// Returns EINVALID error if the DSN is invalid.
+func NewSentry(dsn string) (*Sentry, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, &app.Error{Op: app.CurrentFunctionName(), Code: app.EINVALID, Message: "invalid dsn", Err: err}
+	}
+
+	path := strings.TrimSuffix(u.Path, "/")
+	i := strings.LastIndexByte(path, '/')
+	if u.Scheme == "" || u.Host == "" || u.User == nil || u.User.Username() == "" || i < 0 || i == len(path)-1 {
+		return nil, &app.Error{Op: app.CurrentFunctionName(), Code: app.EINVALID, Message: "invalid dsn"}
+	}
+
+	endpoint := url.URL{Scheme: u.Scheme, Host: u.Host, Path: path[:i] + "/api/" + path[i+1:] + "/envelope/"}
+
+	return &Sentry{
+		dsn:      dsn,
+		endpoint: endpoint.String(),
+		key:      u.User.Username(),
+		now:      time.Now,
+	}, nil
+}
+
+// Report implements Reporter.
+func (s *Sentry) Report(ctx context.Context, err error) error {
+	if s.endpoint == "" {
+		return &app.Error{Op: app.CurrentFunctionName(), Code: app.EINVALID, Message: "sentry is not created with NewSentry"}
+	}
+
+	rates := s.SampleRates
+	if rates == nil {
+		rates = DefaultSampleRates
+	}
+	if !rates.Sampled(err, s.random) {
+		return nil
+	}
+
+	body, err := s.envelope(s.event(err))
+	if err != nil {
+		return app.ErrorWithCode(err, app.ECANNOTENCODE)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return app.OpError(app.CurrentFunctionName(), err)
+	}
+	req.Header.Set("Content-Type", sentryContentType)
+	req.Header.Set("X-Sentry-Auth", "Sentry sentry_version="+sentryVersion+", sentry_client="+sentryClient+", sentry_key="+s.key)
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return app.OpError(app.CurrentFunctionName(), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &app.Error{
+			Op:      app.CurrentFunctionName(),
+			Code:    app.EINTERNAL,
+			Message: "unexpected response status",
+			Fields:  map[string]interface{}{"status": resp.StatusCode},
+		}
+	}
+
+	return nil
+}
+
+type sentryEvent struct {
+	EventID     string                 `json:"event_id"`
+	Timestamp   string                 `json:"timestamp"`
+	Platform    string                 `json:"platform"`
+	Level       string                 `json:"level"`
+	Environment string                 `json:"environment,omitempty"`
+	Release     string                 `json:"release,omitempty"`
+	Tags        map[string]string      `json:"tags"`
+	Extra       map[string]interface{} `json:"extra,omitempty"`
+	Fingerprint []string               `json:"fingerprint"`
+	Exception   sentryExceptions       `json:"exception"`
+	Breadcrumbs *sentryBreadcrumbs     `json:"breadcrumbs,omitempty"`
+}
+
+type sentryExceptions struct {
+	Values []sentryException `json:"values"`
+}
+
+type sentryException struct {
+	Type       string           `json:"type"`
+	Value      string           `json:"value"`
+	Stacktrace sentryStacktrace `json:"stacktrace"`
+}
+
+type sentryStacktrace struct {
+	Frames []sentryFrame `json:"frames"`
+}
+
+type sentryFrame struct {
+	Function string `json:"function"`
+	AbsPath  string `json:"abs_path,omitempty"`
+	Lineno   int    `json:"lineno,omitempty"`
+	InApp    bool   `json:"in_app"`
+}
+
+type sentryBreadcrumbs struct {
+	Values []sentryBreadcrumb `json:"values"`
+}
+
+type sentryBreadcrumb struct {
+	Type     string `json:"type"`
+	Category string `json:"category"`
+	Message  string `json:"message"`
+	Level    string `json:"level"`
+}
+
+func (s *Sentry) event(err error) sentryEvent {
+	code := app.ErrorCode(err)
+	level := sentryLevel(code)
+	fingerprint := app.Fingerprint(err)
+	event := sentryEvent{
+		EventID:     eventID(),
+		Timestamp:   s.now().UTC().Format(time.RFC3339Nano),
+		Platform:    "go",
+		Level:       level,
+		Environment: s.Environment,
+		Release:     s.Release,
+		Tags:        map[string]string{CodeTag: code.String(), FingerprintTag: fingerprint},
+		Extra:       app.RedactedErrorFields(err),
+		Fingerprint: []string{fingerprint},
+	}
+
+	trace := app.ErrorTrace(err)
+	if len(trace) > 0 {
+		event.Breadcrumbs = &sentryBreadcrumbs{}
+		for _, op := range trace {
+			event.Breadcrumbs.Values = append(event.Breadcrumbs.Values, sentryBreadcrumb{Type: "default", Category: "op", Message: op, Level: level})
+		}
+	}
+
+	event.Exception.Values = []sentryException{{
+		Type:       code.String(),
+		Value:      app.DetailMessage(err),
+		Stacktrace: sentryStacktrace{Frames: frames(err, trace)},
+	}}
+
+	return event
+}
+
+// frames returns frames of the captured stack, or pseudo-frames of the trace, the oldest first as Sentry expects.
+func frames(err error, trace []string) []sentryFrame {
+	stack := app.ErrorStack(err).Frames()
+	if len(stack) == 0 {
+		res := make([]sentryFrame, 0, len(trace))
+		for _, op := range trace {
+			res = append(res, sentryFrame{Function: op, InApp: true})
+		}
+		return res
+	}
+
+	res := make([]sentryFrame, 0, len(stack))
+	for i := len(stack) - 1; i >= 0; i-- {
+		res = append(res, sentryFrame{
+			Function: stack[i].Function,
+			AbsPath:  stack[i].File,
+			Lineno:   stack[i].Line,
+			InApp:    !strings.HasPrefix(stack[i].Function, "runtime."),
+		})
+	}
+
+	return res
+}
+
+// envelope returns the envelope with the single event item.
+func (s *Sentry) envelope(event sentryEvent) ([]byte, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := json.Marshal(map[string]string{
+		"event_id": event.EventID,
+		"sent_at":  s.now().UTC().Format(time.RFC3339Nano),
+		"dsn":      s.dsn,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	itemHeader, err := json.Marshal(map[string]interface{}{
+		"type":         "event",
+		"length":       len(payload),
+		"content_type": "application/json",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	for _, line := range [][]byte{header, itemHeader, payload} {
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	return buf.Bytes(), nil
+}
+
+// sentryLevel returns the level of Sentry by app.CodeInfo.LogLevel, "error" by default.
+func sentryLevel(code app.Code) string {
+	info, _ := app.LookupCode(code)
+	switch info.LogLevel {
+	case app.LevelDebug, app.LevelInfo:
+		return info.LogLevel
+	case app.LevelWarn:
+		return "warning"
+	}
+
+	return "error"
+}
+
+func eventID() string {
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
+
+var _ Reporter = &Sentry{}
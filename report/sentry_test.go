@@ -0,0 +1,228 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MrEhbr/app"
+	"github.com/google/go-cmp/cmp"
+)
+
+// envelope is the envelope captured by the server.
+type envelope struct {
+	Auth       string
+	Header     map[string]string
+	ItemHeader map[string]interface{}
+	Event      map[string]interface{}
+}
+
+// newSentry returns the reporter sending envelopes to the test server.
+func newSentry(t *testing.T, status int) (*Sentry, func() []envelope) {
+	t.Helper()
+
+	var envelopes []envelope
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/42/envelope/" || r.Header.Get("Content-Type") != sentryContentType {
+			t.Errorf("unexpected request: %s %s", r.URL.Path, r.Header.Get("Content-Type"))
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+
+		lines := bytes.Split(bytes.TrimSuffix(body, []byte("\n")), []byte("\n"))
+		if len(lines) != 3 {
+			t.Errorf("envelope want 3 lines, got: %s", body)
+			return
+		}
+
+		env := envelope{Auth: r.Header.Get("X-Sentry-Auth")}
+		for i, v := range []interface{}{&env.Header, &env.ItemHeader, &env.Event} {
+			if err := json.Unmarshal(lines[i], v); err != nil {
+				t.Error(err)
+			}
+		}
+		if int(env.ItemHeader["length"].(float64)) != len(lines[2]) {
+			t.Errorf("item length want: %d, got: %v", len(lines[2]), env.ItemHeader["length"])
+		}
+		envelopes = append(envelopes, env)
+
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+
+	s, err := NewSentry(strings.Replace(srv.URL, "://", "://public@", 1) + "/42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.now = func() time.Time { return time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	return s, func() []envelope { return envelopes }
+}
+
+func TestSentry_Report(t *testing.T) {
+	s, envelopes := newSentry(t, http.StatusOK)
+	s.Environment = "test"
+	s.Release = "v1.0.0"
+
+	err := app.OpError("Handler", &app.Error{
+		Op:      "GetUser",
+		Code:    app.EINTERNAL,
+		Message: "db is down",
+		Fields:  map[string]interface{}{"id": 1, "token": "abc"},
+	})
+	if err := s.Report(context.Background(), err); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := len(envelopes()); got != 1 {
+		t.Fatalf("envelopes want: 1, got: %d", got)
+	}
+
+	env := envelopes()[0]
+	if !strings.Contains(env.Auth, "sentry_key=public") {
+		t.Fatalf("auth want key, got: %s", env.Auth)
+	}
+
+	if env.Header["event_id"] != env.Event["event_id"] || len(env.Header["event_id"]) != 32 {
+		t.Fatalf("event id mismatch: %s, %v", env.Header["event_id"], env.Event["event_id"])
+	}
+
+	delete(env.Event, "event_id")
+	fingerprint := app.Fingerprint(err)
+	want := map[string]interface{}{
+		"timestamp":   "2022-01-02T03:04:05Z",
+		"platform":    "go",
+		"level":       "error",
+		"environment": "test",
+		"release":     "v1.0.0",
+		"tags":        map[string]interface{}{CodeTag: "internal", FingerprintTag: fingerprint},
+		"extra":       map[string]interface{}{"id": float64(1), "token": app.RedactedMask},
+		"fingerprint": []interface{}{fingerprint},
+		"exception": map[string]interface{}{"values": []interface{}{map[string]interface{}{
+			"type":  "internal",
+			"value": "Handler: GetUser: <internal> db is down",
+			"stacktrace": map[string]interface{}{"frames": []interface{}{
+				map[string]interface{}{"function": "Handler", "in_app": true},
+				map[string]interface{}{"function": "GetUser", "in_app": true},
+			}},
+		}}},
+		"breadcrumbs": map[string]interface{}{"values": []interface{}{
+			map[string]interface{}{"type": "default", "category": "op", "message": "Handler", "level": "error"},
+			map[string]interface{}{"type": "default", "category": "op", "message": "GetUser", "level": "error"},
+		}},
+	}
+	if diff := cmp.Diff(want, env.Event); diff != "" {
+		t.Fatalf("event mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSentry_Report_sampling(t *testing.T) {
+	s, envelopes := newSentry(t, http.StatusOK)
+	s.SampleRates = DefaultSampleRates.With(SampleRates{app.ECONFLICT: 1})
+
+	errs := []error{
+		nil,
+		errors.New("foo"),
+		app.NewError(app.EBEHAVIOUR, "foo"),
+		app.NewError(app.ENOTFOUND, "foo"),
+		app.NewError(app.ECONFLICT, "foo"),
+	}
+	for _, err := range errs {
+		if err := s.Report(context.Background(), err); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var got []string
+	for _, env := range envelopes() {
+		got = append(got, env.Event["level"].(string)+" "+env.Event["tags"].(map[string]interface{})[CodeTag].(string))
+	}
+
+	want := []string{"error internal", "error undefined_behavior", "warning conflict"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("reported mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSentry_Report_stack(t *testing.T) {
+	app.EnableStackCapture(true)
+	defer app.EnableStackCapture(false)
+
+	s, envelopes := newSentry(t, http.StatusOK)
+	if err := s.Report(context.Background(), app.ErrorWithCode(errors.New("foo"), app.EINTERNAL)); err != nil {
+		t.Fatal(err)
+	}
+
+	exception := envelopes()[0].Event["exception"].(map[string]interface{})["values"].([]interface{})[0].(map[string]interface{})
+	frames := exception["stacktrace"].(map[string]interface{})["frames"].([]interface{})
+	last := frames[len(frames)-1].(map[string]interface{})
+
+	const want = "github.com/MrEhbr/app/report.TestSentry_Report_stack"
+	if last["function"] != want || last["lineno"] == nil {
+		t.Fatalf("last frame want: %s with line, got: %v", want, last)
+	}
+}
+
+func TestSentry_Report_status(t *testing.T) {
+	s, _ := newSentry(t, http.StatusTooManyRequests)
+
+	err := s.Report(context.Background(), errors.New("foo"))
+	if code := app.ErrorCode(err); err == nil || code != app.EINTERNAL {
+		t.Fatalf("want internal error, got: %v", err)
+	}
+
+	if diff := cmp.Diff(map[string]interface{}{"status": http.StatusTooManyRequests}, app.ErrorFields(err)); diff != "" {
+		t.Fatalf("fields mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSentry_Report_zero(t *testing.T) {
+	var s Sentry
+	if err := s.Report(context.Background(), errors.New("foo")); app.ErrorCode(err) != app.EINVALID {
+		t.Fatalf("want invalid error, got: %v", err)
+	}
+}
+
+func TestNewSentry(t *testing.T) {
+	tests := []struct {
+		dsn          string
+		wantEndpoint string
+		wantErr      bool
+	}{
+		{dsn: "https://public@sentry.example.com/1", wantEndpoint: "https://sentry.example.com/api/1/envelope/"},
+		{dsn: "https://public@sentry.example.com/sentry/1/", wantEndpoint: "https://sentry.example.com/sentry/api/1/envelope/"},
+		{dsn: "https://sentry.example.com/1", wantErr: true},
+		{dsn: "https://public@sentry.example.com", wantErr: true},
+		{dsn: "public@sentry.example.com/1", wantErr: true},
+		{dsn: "://", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dsn, func(t *testing.T) {
+			s, err := NewSentry(tt.dsn)
+			if tt.wantErr {
+				if app.ErrorCode(err) != app.EINVALID {
+					t.Fatalf("want invalid error, got: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if s.endpoint != tt.wantEndpoint {
+				t.Fatalf("endpoint want: %s, got: %s", tt.wantEndpoint, s.endpoint)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// AttemptsKey is the key of Error.Fields that holds the number of attempts made by Retrier.
+const AttemptsKey = "attempts"
+
+// IsRetryable reports whether the operation failed with the err may be retried.
+// The first Error.Retryable set in the chain takes precedence over CodeInfo.Retryable of the error code.
+// Context errors are never retryable.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if v, ok := explicit(err, func(e *Error) *bool { return e.Retryable }); ok {
+		return v
+	}
+
+	info, _ := LookupCode(ErrorCode(err))
+	return info.Retryable
+}
+
+// IsTemporary reports whether the failure is temporary.
+// The first Error.Temporary set in the chain takes precedence over CodeInfo.Temporary of the error code,
+// errors implementing Temporary() bool, e.g. net.Error, are respected as well.
+func IsTemporary(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if v, ok := explicit(err, func(e *Error) *bool { return e.Temporary }); ok {
+		return v
+	}
+
+	var temporary interface{ Temporary() bool }
+	if errors.As(err, &temporary) {
+		return temporary.Temporary()
+	}
+
+	info, _ := LookupCode(ErrorCode(err))
+	return info.Temporary
+}
+
+// RetryAfter returns the first non-zero Error.RetryAfter of the chain.
+func RetryAfter(err error) time.Duration {
+	for _, e := range chain(err) {
+		if e.RetryAfter > 0 {
+			return e.RetryAfter
+		}
+	}
+
+	return 0
+}
+
+// WithRetryable returns the err wrapped with the retryability.
+// The err is not modified, because it may be a shared sentinel.
+func WithRetryable(err error, retryable bool) error {
+	if err == nil {
+		return nil
+	}
+
+	return &Error{Op: CallerFunctionName(), Err: err, Retryable: &retryable}
+}
+
+// WithRetryAfter returns the err wrapped with the retry hint.
+// The err is not modified, because it may be a shared sentinel.
+func WithRetryAfter(err error, d time.Duration) error {
+	if err == nil {
+		return nil
+	}
+
+	return &Error{Op: CallerFunctionName(), Err: err, RetryAfter: d}
+}
+
+// explicit returns the first value set in the chain.
+func explicit(err error, get func(*Error) *bool) (bool, bool) {
+	for _, e := range chain(err) {
+		if v := get(e); v != nil {
+			return *v, true
+		}
+	}
+
+	return false, false
+}
+
+// Backoff returns the delay before the next attempt, attempt starts from 1.
+type Backoff func(attempt int) time.Duration
+
+// ExponentialBackoff returns the backoff doubling the delay from base up to limit.
+func ExponentialBackoff(base, limit time.Duration) Backoff {
+	return func(attempt int) time.Duration {
+		delay := base
+		for i := 1; i < attempt && delay < limit; i++ {
+			delay *= 2
+		}
+		if delay > limit {
+			delay = limit
+		}
+
+		return delay
+	}
+}
+
+// Retrier retries operations failed with retryable errors.
+type Retrier struct {
+	// Maximum number of attempts, 1 if not positive.
+	Attempts int
+	// Backoff between attempts, no delay if nil.
+	// Error.RetryAfter is used instead, if it's longer.
+	Backoff Backoff
+}
+
+// Do calls fn until it succeeds, fails with not retryable error, attempts are exhausted or ctx is done.
+// The returned error has the number of made attempts in Fields.
+func (r Retrier) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	op := CallerFunctionName()
+	attempts := r.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+
+		if attempt >= attempts || !IsRetryable(err) {
+			return &Error{Op: op, Err: err, Fields: map[string]interface{}{AttemptsKey: attempt}}
+		}
+
+		var delay time.Duration
+		if r.Backoff != nil {
+			delay = r.Backoff(attempt)
+		}
+		if after := RetryAfter(err); after > delay {
+			delay = after
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return &Error{Op: op, Err: err, Fields: map[string]interface{}{AttemptsKey: attempt}}
+		case <-timer.C:
+		}
+	}
+}
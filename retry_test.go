@@ -0,0 +1,224 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type temporaryError struct{}
+
+func (temporaryError) Error() string   { return "temporary" }
+func (temporaryError) Temporary() bool { return true }
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{
+			err:  nil,
+			want: false,
+		},
+		{
+			err:  errors.New("foo"),
+			want: true,
+		},
+		{
+			err:  &Error{Code: EINVALID},
+			want: false,
+		},
+		{
+			err:  &Error{Code: EINTERNAL},
+			want: true,
+		},
+		{
+			err:  WithRetryable(&Error{Code: EINVALID}, true),
+			want: true,
+		},
+		{
+			err:  OpError("outer", WithRetryable(&Error{Code: EINTERNAL}, false)),
+			want: false,
+		},
+		{
+			err:  WithRetryable(WithRetryable(errors.New("foo"), false), true),
+			want: true,
+		},
+		{
+			err:  fmt.Errorf("%w", context.Canceled),
+			want: false,
+		},
+	}
+
+	for i, tt := range tests {
+		if got := IsRetryable(tt.err); got != tt.want {
+			t.Fatalf("%d. IsRetryable want: %t, got: %t", i, tt.want, got)
+		}
+	}
+}
+
+func TestIsTemporary(t *testing.T) {
+	temporary := true
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{
+			err:  nil,
+			want: false,
+		},
+		{
+			err:  &Error{Code: ENOTFOUND},
+			want: false,
+		},
+		{
+			err:  &Error{Code: EINTERNAL},
+			want: true,
+		},
+		{
+			err:  &Error{Code: ENOTFOUND, Temporary: &temporary},
+			want: true,
+		},
+		{
+			err:  ErrorWithCode(temporaryError{}, ECONFLICT),
+			want: true,
+		},
+	}
+
+	for i, tt := range tests {
+		if got := IsTemporary(tt.err); got != tt.want {
+			t.Fatalf("%d. IsTemporary want: %t, got: %t", i, tt.want, got)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	err := OpError("outer", WithRetryAfter(&Error{Code: EINTERNAL}, time.Second))
+	if got := RetryAfter(err); got != time.Second {
+		t.Fatalf("RetryAfter want: %s, got: %s", time.Second, got)
+	}
+
+	if got := RetryAfter(errors.New("foo")); got != 0 {
+		t.Fatalf("RetryAfter want: 0, got: %s", got)
+	}
+}
+
+func TestWithRetryable_sentinel(t *testing.T) {
+	sentinel := &Error{Code: ECONFLICT}
+
+	if err := WithRetryable(sentinel, true); !IsRetryable(err) {
+		t.Fatal("wrapped error must be retryable")
+	}
+
+	if err := WithRetryAfter(sentinel, time.Second); RetryAfter(err) != time.Second {
+		t.Fatalf("RetryAfter want: %s, got: %s", time.Second, RetryAfter(err))
+	}
+
+	if sentinel.Retryable != nil || sentinel.RetryAfter != 0 {
+		t.Fatalf("sentinel modified: %+v", sentinel)
+	}
+
+	if IsRetryable(sentinel) {
+		t.Fatal("sentinel must not be retryable")
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(time.Millisecond, 5*time.Millisecond)
+	want := []time.Duration{time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond, 5 * time.Millisecond, 5 * time.Millisecond}
+	for i, w := range want {
+		if got := backoff(i + 1); got != w {
+			t.Fatalf("attempt %d want: %s, got: %s", i+1, w, got)
+		}
+	}
+}
+
+func TestRetrier_Do(t *testing.T) {
+	tests := []struct {
+		name         string
+		retrier      Retrier
+		errs         []error
+		wantCalls    int
+		wantAttempts interface{}
+		wantCode     Code
+	}{
+		{
+			name:      "success",
+			retrier:   Retrier{Attempts: 3},
+			errs:      []error{nil},
+			wantCalls: 1,
+		},
+		{
+			name:      "success after retry",
+			retrier:   Retrier{Attempts: 3, Backoff: ExponentialBackoff(time.Microsecond, time.Millisecond)},
+			errs:      []error{&Error{Code: EINTERNAL}, nil},
+			wantCalls: 2,
+		},
+		{
+			name:         "not retryable",
+			retrier:      Retrier{Attempts: 3},
+			errs:         []error{&Error{Code: EINVALID}},
+			wantCalls:    1,
+			wantAttempts: 1,
+			wantCode:     EINVALID,
+		},
+		{
+			name:         "attempts exhausted",
+			retrier:      Retrier{Attempts: 3},
+			errs:         []error{&Error{Code: EINTERNAL}, &Error{Code: EINTERNAL}, &Error{Code: EINTERNAL}},
+			wantCalls:    3,
+			wantAttempts: 3,
+			wantCode:     EINTERNAL,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := tt.retrier.Do(context.Background(), func(context.Context) error {
+				err := tt.errs[calls]
+				calls++
+				return err
+			})
+
+			if calls != tt.wantCalls {
+				t.Fatalf("calls want: %d, got: %d", tt.wantCalls, calls)
+			}
+
+			if tt.wantAttempts == nil {
+				if err != nil {
+					t.Fatalf("want nil, got: %v", err)
+				}
+				return
+			}
+
+			if got := ErrorFields(err)[AttemptsKey]; got != tt.wantAttempts {
+				t.Fatalf("attempts want: %v, got: %v", tt.wantAttempts, got)
+			}
+
+			if got := ErrorCode(err); got != tt.wantCode {
+				t.Fatalf("code want: %s, got: %s", tt.wantCode, got)
+			}
+		})
+	}
+
+	t.Run("context done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		err := Retrier{Attempts: 3, Backoff: ExponentialBackoff(time.Hour, time.Hour)}.Do(ctx, func(context.Context) error {
+			calls++
+			cancel()
+			return &Error{Code: EINTERNAL}
+		})
+
+		if calls != 1 {
+			t.Fatalf("calls want: 1, got: %d", calls)
+		}
+
+		if got := ErrorFields(err)[AttemptsKey]; got != 1 {
+			t.Fatalf("attempts want: 1, got: %v", got)
+		}
+	})
+}
@@ -133,7 +133,8 @@ go.depaware-check: go.tidy
 go.build:
 	@set -xe; \
 	  for dir in $(GOBINS); do ( \
-			CGO_ENABLED=$(CGO_ENABLED) $(GO) build $(GO_BUILD_OPTS) -o .bin/`basename $$dir`/`basename $$dir`  $$dir/main.go; \
+			cd $$dir; \
+			CGO_ENABLED=$(CGO_ENABLED) $(GO) build $(GO_BUILD_OPTS) -o $(CURDIR)/.bin/`basename $$dir`/`basename $$dir` main.go; \
 	  ); done
 
 BUILD_STEPS += go.build
@@ -0,0 +1,101 @@
+package app
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// maxStackDepth is the maximum number of captured frames.
+const maxStackDepth = 32
+
+var stackCapture atomic.Bool
+
+// EnableStackCapture enables or disables capturing of the stack trace
+// when an Error is created with ErrorWithCode, OpError or NewError.
+// Capturing is disabled by default.
+func EnableStackCapture(enable bool) {
+	stackCapture.Store(enable)
+}
+
+// Stack is a stack trace captured as program counters.
+// Frames are resolved lazily, so capturing is cheap.
+type Stack []uintptr
+
+// Frames returns the resolved stack frames.
+func (s Stack) Frames() []runtime.Frame {
+	if len(s) == 0 {
+		return nil
+	}
+
+	frames := make([]runtime.Frame, 0, len(s))
+	iter := runtime.CallersFrames(s)
+	for {
+		frame, more := iter.Next()
+		frames = append(frames, frame)
+		if !more {
+			break
+		}
+	}
+
+	return frames
+}
+
+// Strings returns frames in form of "function file:line".
+func (s Stack) Strings() []string {
+	frames := s.Frames()
+	res := make([]string, 0, len(frames))
+	for _, frame := range frames {
+		res = append(res, frame.Function+" "+frame.File+":"+strconv.Itoa(frame.Line))
+	}
+
+	return res
+}
+
+// String returns frames one per line in form of:
+//
+//	function
+//		file:line
+func (s Stack) String() string {
+	var buf strings.Builder
+	for i, frame := range s.Frames() {
+		if i > 0 {
+			buf.WriteRune('\n')
+		}
+		buf.WriteString(frame.Function)
+		buf.WriteString("\n\t")
+		buf.WriteString(frame.File)
+		buf.WriteRune(':')
+		buf.WriteString(strconv.Itoa(frame.Line))
+	}
+
+	return buf.String()
+}
+
+// ErrorStack returns the deepest captured stack of the error chain, if available.
+// For Errors the stack of the dominant error is returned.
+func ErrorStack(err error) Stack {
+	var stack Stack
+	for _, e := range chain(err) {
+		if len(e.Stack) > 0 {
+			stack = e.Stack
+		}
+	}
+
+	return stack
+}
+
+// newStack captures the stack of the constructor's caller
+// if capturing is enabled and the err has no captured stack yet.
+func newStack(err error) Stack {
+	if !stackCapture.Load() || ErrorStack(err) != nil {
+		return nil
+	}
+
+	pcs := make([]uintptr, maxStackDepth)
+	// Skip runtime.Callers, newStack and the constructor
+	n := runtime.Callers(3, pcs)
+
+	return pcs[:n]
+}
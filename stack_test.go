@@ -0,0 +1,132 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func withStackCapture(t testing.TB) {
+	EnableStackCapture(true)
+	t.Cleanup(func() {
+		EnableStackCapture(false)
+	})
+}
+
+func stackFunc() error {
+	return ErrorWithCode(errors.New("test"), ETEST)
+}
+
+func TestErrorStack(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		if stack := ErrorStack(stackFunc()); stack != nil {
+			t.Fatalf("want nil stack, got: %v", stack.Strings())
+		}
+	})
+
+	withStackCapture(t)
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "ErrorWithCode",
+			err:  stackFunc(),
+			want: "github.com/MrEhbr/app.stackFunc",
+		},
+		{
+			name: "OpError",
+			err:  OpError("test", errors.New("test")),
+			want: "github.com/MrEhbr/app.TestErrorStack",
+		},
+		{
+			name: "OpErrorOrNil",
+			err:  OpErrorOrNil("test", errors.New("test")),
+			want: "github.com/MrEhbr/app.TestErrorStack",
+		},
+		{
+			name: "NewError",
+			err:  NewError(ETEST, "test"),
+			want: "github.com/MrEhbr/app.TestErrorStack",
+		},
+		{
+			name: "deepest stack is kept",
+			err:  fmt.Errorf("%w", OpError("outer", stackFunc())),
+			want: "github.com/MrEhbr/app.stackFunc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frames := ErrorStack(tt.err).Frames()
+			if len(frames) == 0 {
+				t.Fatal("stack is not captured")
+			}
+
+			if frames[0].Function != tt.want {
+				t.Fatalf("first frame want: %s, got: %s", tt.want, frames[0].Function)
+			}
+		})
+	}
+
+	t.Run("inner stack is not replaced", func(t *testing.T) {
+		inner := stackFunc()
+		err := OpError("outer", inner)
+		target := &Error{}
+		if !errors.As(err, &target) || target.Stack != nil {
+			t.Fatal("outer error must not capture stack")
+		}
+	})
+}
+
+var errStackSentinel = &Error{Code: ENOTFOUND}
+
+func stackSentinelFoo() error {
+	return OpError("foo", errStackSentinel)
+}
+
+func stackSentinelBar() error {
+	return OpError("bar", errStackSentinel)
+}
+
+func TestErrorStack_sentinel(t *testing.T) {
+	withStackCapture(t)
+
+	foo, bar := stackSentinelFoo(), stackSentinelBar()
+	if errStackSentinel.Stack != nil {
+		t.Fatal("sentinel must not capture stack")
+	}
+
+	if got := ErrorStack(foo).Frames()[0].Function; got != "github.com/MrEhbr/app.stackSentinelFoo" {
+		t.Fatalf("first error frame want: stackSentinelFoo, got: %s", got)
+	}
+
+	if got := ErrorStack(bar).Frames()[0].Function; got != "github.com/MrEhbr/app.stackSentinelBar" {
+		t.Fatalf("second error frame want: stackSentinelBar, got: %s", got)
+	}
+}
+
+func TestStack_String(t *testing.T) {
+	withStackCapture(t)
+
+	got := ErrorStack(stackFunc()).String()
+	if !strings.HasPrefix(got, "github.com/MrEhbr/app.stackFunc\n\t") {
+		t.Fatalf("unexpected stack:\n%s", got)
+	}
+
+	if !strings.Contains(got, "stack_test.go:") {
+		t.Fatalf("stack doesn't contain file:\n%s", got)
+	}
+}
+
+func BenchmarkErrorWithCode_stack(b *testing.B) {
+	withStackCapture(b)
+
+	err := errors.New("test")
+	for i := 0; i < b.N; i++ {
+		_ = ErrorWithCode(err, ETEST)
+	}
+}
@@ -0,0 +1,127 @@
+package app
+
+// ViolationsKey is the key of Error.Fields that holds validation violations.
+const ViolationsKey = "violations"
+
+// Violation describes a failed validation rule of an input field.
+type Violation struct {
+	// Path of the field, e.g. "user.emails[0]".
+	Field string `json:"field"`
+	// Name of the failed rule, e.g. "required".
+	Rule string `json:"rule"`
+	// Rejected value.
+	Value interface{} `json:"value,omitempty"`
+	// Human-readable message.
+	Message string `json:"message"`
+}
+
+// Validation collects violations and builds EINVALID error.
+type Validation struct {
+	op         string
+	message    string
+	violations []Violation
+}
+
+// NewValidation returns a validation builder, caller function name is used as Op.
+func NewValidation() *Validation {
+	return &Validation{op: CallerFunctionName()}
+}
+
+// Message sets the message of the resulting error.
+func (v *Validation) Message(message string) *Validation {
+	v.message = message
+	return v
+}
+
+// Add adds violation of the rule by the field.
+func (v *Validation) Add(field, rule string, value interface{}, message string) *Validation {
+	v.violations = append(v.violations, Violation{
+		Field:   field,
+		Rule:    rule,
+		Value:   value,
+		Message: message,
+	})
+	return v
+}
+
+// Check adds violation if ok is false.
+func (v *Validation) Check(ok bool, field, rule string, value interface{}, message string) *Validation {
+	if !ok {
+		v.Add(field, rule, value, message)
+	}
+	return v
+}
+
+// Violations returns the collected violations.
+func (v *Validation) Violations() []Violation {
+	return v.violations
+}
+
+// Err returns EINVALID error with violations in Fields, or nil if there are no violations.
+func (v *Validation) Err() error {
+	if len(v.violations) == 0 {
+		return nil
+	}
+
+	message := v.message
+	if message == "" {
+		message = "validation failed"
+	}
+
+	return &Error{
+		Op:      v.op,
+		Code:    EINVALID,
+		Message: message,
+		Fields: map[string]interface{}{
+			ViolationsKey: append([]Violation{}, v.violations...),
+		},
+		Stack: newStack(nil),
+	}
+}
+
+// ErrorViolations returns violations of the error chain, if available.
+func ErrorViolations(err error) []Violation {
+	var violations []Violation
+	for _, e := range flatten(err) {
+		if v, ok := e.Fields[ViolationsKey].([]Violation); ok {
+			violations = append(violations, v...)
+		}
+	}
+
+	return violations
+}
+
+// flatten returns all *Error of the err chain including branches of Errors.
+func flatten(err error) []*Error {
+	var res []*Error
+	for err != nil {
+		target, errs := find(err)
+		if errs != nil {
+			for _, e := range errs {
+				res = append(res, flatten(e)...)
+			}
+			break
+		}
+		if target == nil {
+			break
+		}
+		res = append(res, target)
+		err = target.Err
+	}
+
+	return res
+}
+
+// mergeViolations concatenates violations of dst and src, if both are violations.
+func mergeViolations(dst, src interface{}) (interface{}, bool) {
+	d, ok := dst.([]Violation)
+	if !ok {
+		return nil, false
+	}
+	s, ok := src.([]Violation)
+	if !ok {
+		return nil, false
+	}
+
+	return append(append([]Violation{}, d...), s...), true
+}
@@ -0,0 +1,74 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func validateUser(name string, age int) error {
+	return NewValidation().
+		Check(name != "", "name", "required", name, "name is required").
+		Check(age >= 18, "age", "min", age, "must be at least 18").
+		Err()
+}
+
+func TestValidation_Err(t *testing.T) {
+	if err := validateUser("foo", 18); err != nil {
+		t.Fatalf("want nil, got: %v", err)
+	}
+
+	err := validateUser("", 10)
+	target := &Error{}
+	if !errors.As(err, &target) {
+		t.Fatalf("want *Error, got: %T", err)
+	}
+
+	if target.Code != EINVALID {
+		t.Fatalf("Code want: %s, got: %s", EINVALID, target.Code)
+	}
+
+	if target.Message != "validation failed" {
+		t.Fatalf("Message want: validation failed, got: %s", target.Message)
+	}
+
+	if target.Op != "github.com/MrEhbr/app.validateUser" {
+		t.Fatalf("Op want: github.com/MrEhbr/app.validateUser, got: %s", target.Op)
+	}
+
+	want := []Violation{
+		{Field: "name", Rule: "required", Value: "", Message: "name is required"},
+		{Field: "age", Rule: "min", Value: 10, Message: "must be at least 18"},
+	}
+	if diff := cmp.Diff(want, ErrorViolations(err)); diff != "" {
+		t.Errorf("ErrorViolations() mismatch (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(map[string]interface{}{ViolationsKey: want}, ErrorFields(OpError("outer", err))); diff != "" {
+		t.Errorf("ErrorFields() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestErrorViolations(t *testing.T) {
+	first := NewValidation().Add("name", "required", nil, "name is required").Err()
+	second := NewValidation().Message("invalid address").Add("address.zip", "format", "abc", "invalid zip").Err()
+
+	want := []Violation{
+		{Field: "name", Rule: "required", Message: "name is required"},
+		{Field: "address.zip", Rule: "format", Value: "abc", Message: "invalid zip"},
+	}
+
+	err := Join(first, second)
+	if diff := cmp.Diff(want, ErrorViolations(err)); diff != "" {
+		t.Errorf("ErrorViolations() mismatch (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(map[string]interface{}{ViolationsKey: want}, ErrorFields(err)); diff != "" {
+		t.Errorf("ErrorFields() mismatch (-want +got):\n%s", diff)
+	}
+
+	if got := ErrorViolations(errors.New("foo")); got != nil {
+		t.Fatalf("want nil, got: %v", got)
+	}
+}